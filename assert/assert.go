@@ -11,16 +11,7 @@ import (
 
 func output(t testing.TB, common string, out []any) {
 	t.Helper()
-
-	if len(out) == 0 {
-		t.Fatal(common)
-		return
-	}
-	if str, ok := out[0].(string); ok {
-		t.Fatalf(str, out[1:]...)
-		return
-	}
-	panic("assert: output argument must be a format string and its args")
+	report(t, Failure{Message: common}, out)
 }
 
 func isNil(v any) bool {
@@ -42,23 +33,28 @@ func isNil(v any) bool {
 func Nil(t testing.TB, v any, out ...any) {
 	t.Helper()
 	if !isNil(v) {
-		common := fmt.Sprintf("expected nil value, got %v", v)
-		output(t, common, out)
+		common := fmt.Sprintf("expected nil value%s, got %s", quoted(0, "for"), format(v))
+		report(t, Failure{Message: common, Expected: "nil", Actual: format(v)}, out)
 	}
 }
 
 func NotNil(t testing.TB, v any, out ...any) {
 	t.Helper()
 	if isNil(v) {
-		output(t, "expected not nil value", out)
+		output(t, "expected not nil value"+quoted(0, "for"), out)
 	}
 }
 
+// isZero tells whether v is the zero value of its type, nil included.
+func isZero(v any) bool {
+	return v == nil || reflect.ValueOf(v).IsZero()
+}
+
 func Zero[T any](t testing.TB, v T, out ...any) {
 	t.Helper()
 
-	if !reflect.ValueOf(v).IsZero() {
-		common := fmt.Sprintf("expected zero value for the type %T", v)
+	if !isZero(v) {
+		common := fmt.Sprintf("expected zero value for the type %T%s", v, quoted(0, "from"))
 		output(t, common, out)
 	}
 }
@@ -66,40 +62,25 @@ func Zero[T any](t testing.TB, v T, out ...any) {
 func NotZero[T any](t testing.TB, v T, out ...any) {
 	t.Helper()
 
-	if reflect.ValueOf(v).IsZero() {
-		common := fmt.Sprintf("expected non-zero value for the type %T", v)
+	if isZero(v) {
+		common := fmt.Sprintf("expected non-zero value for the type %T%s", v, quoted(0, "from"))
 		output(t, common, out)
 	}
 }
 
+// isEmpty tells whether v is nil, has no elements or is the zero value of
+// its type. Equal methods aren't used: a value equal to its zero value by
+// its Equal method isn't empty for it.
 func isEmpty(v any) bool {
-	if isNil(v) {
-		return true
-	}
-
-	value := reflect.ValueOf(v)
-
-	switch value.Kind() {
-	case reflect.Array,
-		reflect.Chan,
-		reflect.Map,
-		reflect.Slice,
-		reflect.String:
-		return value.Len() == 0
-	case reflect.Ptr, reflect.UnsafePointer:
-		return isEmpty(value.Elem().Interface())
-	default:
-		zero := reflect.Zero(value.Type())
-		return reflect.DeepEqual(value, zero)
-	}
+	return newComparer([]Option{IgnoreEqualMethods()}).empty(reflect.ValueOf(v))
 }
 
 func Empty[T any](t testing.TB, v T, out ...any) {
 	t.Helper()
 
 	if !isEmpty(v) {
-		common := fmt.Sprintf("expected empty, but got %v", v)
-		output(t, common, out)
+		common := fmt.Sprintf("expected empty%s, but got %s", quoted(0, "for"), format(v))
+		report(t, Failure{Message: common, Actual: format(v)}, out)
 	}
 }
 
@@ -107,7 +88,7 @@ func NotEmpty[T any](t testing.TB, v T, out ...any) {
 	t.Helper()
 
 	if isEmpty(v) {
-		common := fmt.Sprintf("expected not empty, but got %v", v)
+		common := fmt.Sprintf("expected not empty%s, but got %s", quoted(0, "for"), format(v))
 		output(t, common, out)
 	}
 }
@@ -116,7 +97,7 @@ func True(t testing.TB, got bool, out ...any) {
 	t.Helper()
 
 	if !got {
-		common := "didn't get true"
+		common := "didn't get true" + quoted(0, "from")
 		output(t, common, out)
 	}
 }
@@ -125,26 +106,28 @@ func False(t testing.TB, got bool, out ...any) {
 	t.Helper()
 
 	if got {
-		common := "didn't get false"
+		common := "didn't get false" + quoted(0, "from")
 		output(t, common, out)
 	}
 }
 
 func isEqual[T any](a, b T) bool {
-	valA := reflect.ValueOf(a)
-	if valA.Kind() == reflect.Func {
-		valB := reflect.ValueOf(b)
-		return valB.Kind() == reflect.Func && valA.Pointer() == valB.Pointer()
-	}
-	return reflect.DeepEqual(a, b)
+	return len(compare(a, b, nil)) == 0
 }
 
+// Equal compares a and b deeply, using the Equal methods of the values
+// found on the way, as described by EqualOpts. Functions, at any depth, are
+// equal when they're the same function, or both nil.
 func Equal[T any](t testing.TB, a, b T, out ...any) {
 	t.Helper()
 
-	if !isEqual(a, b) {
-		common := fmt.Sprintf("expected equal values, but got %v and %v", a, b)
-		output(t, common, out)
+	if diffs := compare(a, b, nil); len(diffs) > 0 {
+		f := Failure{Expected: format(b), Actual: format(a)}
+		f.Message = fmt.Sprintf("expected equal values, but got %s and %s", f.Actual, f.Expected)
+		if len(diffs) > 1 || diffs[0].path != "" {
+			f.Diff = formatDiffs(diffs)
+		}
+		report(t, f, out)
 	}
 }
 
@@ -152,7 +135,7 @@ func NotEqual[T any](t testing.TB, a, b T, out ...any) {
 	t.Helper()
 
 	if isEqual(a, b) {
-		common := fmt.Sprintf("expected different values, but %v is equal to %v ", a, b)
+		common := fmt.Sprintf("expected different values, but %s is equal to %s ", format(a), format(b))
 		output(t, common, out)
 	}
 }
@@ -161,7 +144,7 @@ func EqualFunc[A, B any](t testing.TB, a A, b B, cmp func(A, B) bool, out ...any
 	t.Helper()
 
 	if !cmp(a, b) {
-		common := fmt.Sprintf("%v and %v can't be the same accordingly to comparator", a, b)
+		common := fmt.Sprintf("%s and %s can't be the same accordingly to comparator", format(a), format(b))
 		output(t, common, out)
 	}
 }
@@ -170,7 +153,7 @@ func NotEqualFunc[A, B any](t testing.TB, a A, b B, cmp func(A, B) bool, out ...
 	t.Helper()
 
 	if cmp(a, b) {
-		common := fmt.Sprintf("%v and %v are the same accordingly to comparator", a, b)
+		common := fmt.Sprintf("%s and %s are the same accordingly to comparator", format(a), format(b))
 		output(t, common, out)
 	}
 }
@@ -179,8 +162,9 @@ func Error(t testing.TB, got, want error, out ...any) {
 	t.Helper()
 
 	if got != want {
-		common := fmt.Sprintf("expected error %v, but got %v", want, got)
-		output(t, common, out)
+		f := Failure{Expected: format(want), Actual: format(got)}
+		f.Message = fmt.Sprintf("expected error %s, but got %s", f.Expected, f.Actual)
+		report(t, f, out)
 	}
 }
 
@@ -188,7 +172,7 @@ func NotError(t testing.TB, got, nwant error, out ...any) {
 	t.Helper()
 
 	if got == nwant {
-		common := fmt.Sprintf("didn't expected error %v, but got it", nwant)
+		common := fmt.Sprintf("didn't expected error %s, but got it", format(nwant))
 		output(t, common, out)
 	}
 }
@@ -215,7 +199,7 @@ func contains[T comparable, S StringOrSet[T]](s S, lf T) bool {
 		}
 	}
 	for v := range collec {
-		if v == lf {
+		if isEqual(v, lf) {
 			return true
 		}
 	}
@@ -226,7 +210,7 @@ func Contains[T comparable, S StringOrSet[T]](t testing.TB, s S, lf T, out ...an
 	t.Helper()
 
 	if !contains(s, lf) {
-		common := fmt.Sprintf("%v isn't in the collection", lf)
+		common := fmt.Sprintf("%s isn't in the collection", format(lf))
 		output(t, common, out)
 	}
 }
@@ -235,7 +219,7 @@ func NotContains[T comparable, S StringOrSet[T]](t testing.TB, s S, lf T, out ..
 	t.Helper()
 
 	if contains(s, lf) {
-		common := fmt.Sprintf("%v is in the collection", lf)
+		common := fmt.Sprintf("%s is in the collection", format(lf))
 		output(t, common, out)
 	}
 }
@@ -318,6 +302,36 @@ func HasNoSuffix(t testing.TB, s string, sfx string, out ...any) {
 	}
 }
 
+// ContainsLines asserts the lines are whole lines of s, appearing in the
+// given order, though not necessarily one after the other.
+func ContainsLines(t testing.TB, s string, lines []string, out ...any) {
+	t.Helper()
+
+	if i := missingLine(s, lines); i >= 0 {
+		common := fmt.Sprintf("line %q isn't in %s", lines[i], format(s))
+		if i > 0 {
+			common = fmt.Sprintf("line %q isn't after line %q in %s", lines[i], lines[i-1], format(s))
+		}
+		output(t, common, out)
+	}
+}
+
+// missingLine returns the index of the first of lines not found in order
+// in s, or -1 if all are.
+func missingLine(s string, lines []string) int {
+	have := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+	i := 0
+	for _, line := range have {
+		if i < len(lines) && line == lines[i] {
+			i++
+		}
+	}
+	if i < len(lines) {
+		return i
+	}
+	return -1
+}
+
 func Panics(t testing.TB, fn func(), out ...any) {
 	t.Helper()
 
@@ -338,7 +352,7 @@ func NotPanics(t testing.TB, fn func(), out ...any) {
 	defer func() {
 		t.Helper()
 		if r := recover(); r != nil {
-			common := fmt.Sprintf("did panic, %v", r)
+			common := fmt.Sprintf("did panic, %s", format(r))
 			output(t, common, out)
 		}
 	}()
@@ -352,7 +366,7 @@ func PanicIs(t testing.TB, fn func(), exp any, out ...any) {
 	defer func() {
 		t.Helper()
 		if r := recover(); r != exp {
-			common := fmt.Sprintf("can't get the expected panic, got %v", r)
+			common := fmt.Sprintf("can't get the expected panic, got %s", format(r))
 			output(t, common, out)
 		}
 	}()
@@ -364,7 +378,7 @@ func Greater[T cmp.Ordered](t testing.TB, a, b T) {
 	t.Helper()
 
 	if a <= b {
-		t.Fatalf("%v is actually smaller than %v", a, b)
+		output(t, fmt.Sprintf("%s is actually smaller than %s", format(a), format(b)), nil)
 	}
 }
 
@@ -372,6 +386,6 @@ func Smaller[T cmp.Ordered](t testing.TB, a, b T) {
 	t.Helper()
 
 	if a >= b {
-		t.Fatalf("%v is actually greater than %v", a, b)
+		output(t, fmt.Sprintf("%s is actually greater than %s", format(a), format(b)), nil)
 	}
 }
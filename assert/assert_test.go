@@ -525,6 +525,19 @@ func TestHasNoSuffix(t *testing.T) {
 	})
 }
 
+func TestContainsLines(t *testing.T) {
+	out := "building...\nlistening on :5000\nready\n"
+	assertSuccess(t, "lines are in order", func(t testing.TB) {
+		assert.ContainsLines(t, out, []string{"building...", "ready"})
+	})
+	assertFailure(t, "line is only part of a line", func(t testing.TB) {
+		assert.ContainsLines(t, out, []string{"listening"})
+	})
+	assertFailure(t, "lines are out of order", func(t testing.TB) {
+		assert.ContainsLines(t, out, []string{"ready", "building..."})
+	})
+}
+
 type writer struct {
 	b []byte
 }
@@ -0,0 +1,254 @@
+package assert
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// Assert binds the assertions of this package to a testing.TB.
+//
+// Assertions comparing values of the same type are functions taking the
+// Assert, such as EqualOn, so the types are checked at compile time. Others
+// taking generic comparators (EqualFunc, ContainsFunc, ...) are called as
+// package functions with the bound testing.TB, e.g.
+// assert.EqualFunc(a.TB(), got, want, eq).
+type Assert struct {
+	tb      testing.TB
+	message string
+	context []any
+	soft    *failureLog
+}
+
+func New(t testing.TB) *Assert {
+	if t == nil {
+		panic("assert: nil testing.TB")
+	}
+	return &Assert{tb: t}
+}
+
+func (a *Assert) clone() *Assert {
+	c := *a
+	c.context = append([]any(nil), a.context...)
+	return &c
+}
+
+// WithMessage returns a copy of a whose failures are prefixed with the
+// formatted message.
+func (a *Assert) WithMessage(format string, args ...any) *Assert {
+	c := a.clone()
+	c.message = fmt.Sprintf(format, args...)
+	return c
+}
+
+// WithContext returns a copy of a whose failures also show the given
+// key/value pairs.
+func (a *Assert) WithContext(kv ...any) *Assert {
+	if len(kv)%2 != 0 {
+		panic("assert: context must be key/value pairs")
+	}
+	c := a.clone()
+	c.context = append(c.context, kv...)
+	return c
+}
+
+// Soft returns a copy of a that records failures instead of stopping the
+// test. The recorded failures are reported together on test cleanup.
+func (a *Assert) Soft() *Assert {
+	c := a.clone()
+	if c.soft == nil {
+		log := &failureLog{}
+		a.tb.Cleanup(func() {
+			if log.len() > 0 {
+				a.tb.Error(log.report())
+			}
+		})
+		c.soft = log
+	}
+	return c
+}
+
+// TB returns the testing.TB the assertions of a fail through.
+func (a *Assert) TB() testing.TB {
+	return &boundTB{a.tb, a}
+}
+
+func (a *Assert) decorate(msg string) string {
+	var pfx strings.Builder
+	pfx.WriteString(a.message)
+	if len(a.context) > 0 {
+		if pfx.Len() > 0 {
+			pfx.WriteByte(' ')
+		}
+		pfx.WriteByte('[')
+		for i := 0; i < len(a.context); i += 2 {
+			if i > 0 {
+				pfx.WriteString(", ")
+			}
+			fmt.Fprintf(&pfx, "%v=%v", a.context[i], a.context[i+1])
+		}
+		pfx.WriteByte(']')
+	}
+	if pfx.Len() == 0 {
+		return msg
+	}
+	return pfx.String() + ": " + msg
+}
+
+func (a *Assert) fail(msg string) {
+	a.tb.Helper()
+
+	msg = a.decorate(msg)
+	if a.soft != nil {
+		a.soft.add(msg)
+		return
+	}
+	a.tb.Fatal(msg)
+}
+
+type boundTB struct {
+	testing.TB
+	a *Assert
+}
+
+func (b *boundTB) Fatal(args ...any) {
+	b.TB.Helper()
+	b.a.fail(fmt.Sprint(args...))
+}
+
+func (b *boundTB) Fatalf(format string, args ...any) {
+	b.TB.Helper()
+	b.a.fail(fmt.Sprintf(format, args...))
+}
+
+func (a *Assert) Nil(v any, out ...any) {
+	a.tb.Helper()
+	Nil(a.TB(), v, out...)
+}
+
+func (a *Assert) NotNil(v any, out ...any) {
+	a.tb.Helper()
+	NotNil(a.TB(), v, out...)
+}
+
+func (a *Assert) Zero(v any, out ...any) {
+	a.tb.Helper()
+	Zero(a.TB(), v, out...)
+}
+
+func (a *Assert) NotZero(v any, out ...any) {
+	a.tb.Helper()
+	NotZero(a.TB(), v, out...)
+}
+
+func (a *Assert) Empty(v any, out ...any) {
+	a.tb.Helper()
+	Empty(a.TB(), v, out...)
+}
+
+func (a *Assert) NotEmpty(v any, out ...any) {
+	a.tb.Helper()
+	NotEmpty(a.TB(), v, out...)
+}
+
+func (a *Assert) True(got bool, out ...any) {
+	a.tb.Helper()
+	True(a.TB(), got, out...)
+}
+
+func (a *Assert) False(got bool, out ...any) {
+	a.tb.Helper()
+	False(a.TB(), got, out...)
+}
+
+func (a *Assert) Error(got, want error, out ...any) {
+	a.tb.Helper()
+	Error(a.TB(), got, want, out...)
+}
+
+func (a *Assert) NotError(got, nwant error, out ...any) {
+	a.tb.Helper()
+	NotError(a.TB(), got, nwant, out...)
+}
+
+func (a *Assert) HasPrefix(s, pfx string, out ...any) {
+	a.tb.Helper()
+	HasPrefix(a.TB(), s, pfx, out...)
+}
+
+func (a *Assert) HasNoPrefix(s, pfx string, out ...any) {
+	a.tb.Helper()
+	HasNoPrefix(a.TB(), s, pfx, out...)
+}
+
+func (a *Assert) HasSuffix(s, sfx string, out ...any) {
+	a.tb.Helper()
+	HasSuffix(a.TB(), s, sfx, out...)
+}
+
+func (a *Assert) HasNoSuffix(s, sfx string, out ...any) {
+	a.tb.Helper()
+	HasNoSuffix(a.TB(), s, sfx, out...)
+}
+
+func (a *Assert) ContainsLines(s string, lines []string, out ...any) {
+	a.tb.Helper()
+	ContainsLines(a.TB(), s, lines, out...)
+}
+
+func (a *Assert) Panics(fn func(), out ...any) {
+	a.tb.Helper()
+	Panics(a.TB(), fn, out...)
+}
+
+func (a *Assert) NotPanics(fn func(), out ...any) {
+	a.tb.Helper()
+	NotPanics(a.TB(), fn, out...)
+}
+
+func (a *Assert) PanicIs(fn func(), exp any, out ...any) {
+	a.tb.Helper()
+	PanicIs(a.TB(), fn, exp, out...)
+}
+
+// EqualOn is Equal failing through a.
+func EqualOn[T any](a *Assert, x, y T, out ...any) {
+	a.tb.Helper()
+	Equal(a.TB(), x, y, out...)
+}
+
+func NotEqualOn[T any](a *Assert, x, y T, out ...any) {
+	a.tb.Helper()
+	NotEqual(a.TB(), x, y, out...)
+}
+
+func EqualOptsOn[T any](a *Assert, x, y T, opts ...Option) {
+	a.tb.Helper()
+	EqualOpts(a.TB(), x, y, opts...)
+}
+
+func NotEqualOptsOn[T any](a *Assert, x, y T, opts ...Option) {
+	a.tb.Helper()
+	NotEqualOpts(a.TB(), x, y, opts...)
+}
+
+func ContainsOn[T comparable, S StringOrSet[T]](a *Assert, s S, lf T, out ...any) {
+	a.tb.Helper()
+	Contains(a.TB(), s, lf, out...)
+}
+
+func NotContainsOn[T comparable, S StringOrSet[T]](a *Assert, s S, lf T, out ...any) {
+	a.tb.Helper()
+	NotContains(a.TB(), s, lf, out...)
+}
+
+func GreaterOn[T cmp.Ordered](a *Assert, x, y T) {
+	a.tb.Helper()
+	Greater(a.TB(), x, y)
+}
+
+func SmallerOn[T cmp.Ordered](a *Assert, x, y T) {
+	a.tb.Helper()
+	Smaller(a.TB(), x, y)
+}
@@ -0,0 +1,155 @@
+package assert_test
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/xandalm/go-testing/assert"
+)
+
+type recordingTester struct {
+	*testing.T
+	fatals   []string
+	errors   []string
+	cleanups []func()
+}
+
+func (t *recordingTester) Fatal(args ...any) {
+	t.fatals = append(t.fatals, fmt.Sprint(args...))
+}
+
+func (t *recordingTester) Fatalf(format string, args ...any) {
+	t.fatals = append(t.fatals, fmt.Sprintf(format, args...))
+}
+
+func (t *recordingTester) Error(args ...any) {
+	t.errors = append(t.errors, fmt.Sprint(args...))
+}
+
+func (t *recordingTester) Errorf(format string, args ...any) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func (t *recordingTester) Cleanup(fn func()) {
+	t.cleanups = append(t.cleanups, fn)
+}
+
+func (t *recordingTester) runCleanups() {
+	for _, fn := range slices.Backward(t.cleanups) {
+		fn()
+	}
+}
+
+func TestAssert(t *testing.T) {
+	assertSuccess(t, "methods that should pass", func(t testing.TB) {
+		a := assert.New(t)
+		a.Nil(nil)
+		a.NotNil(errFoo)
+		a.Zero(0)
+		a.NotZero("foo")
+		a.Empty([]int{})
+		a.NotEmpty(map[int]int{1: 1})
+		a.True(true)
+		a.False(false)
+		assert.EqualOn(a, pointer{1, 2}, pointer{1, 2})
+		assert.NotEqualOn(a, 1, 2)
+		assert.EqualOptsOn(a, pointer{1, 2}, pointer{1, 3}, assert.IgnoreFields("Y"))
+		assert.NotEqualOptsOn(a, pointer{1, 2}, pointer{1, 3})
+		a.Error(errFoo, errFoo)
+		a.NotError(errFoo, nil)
+		assert.ContainsOn(a, []int{1, 2, 3}, 2)
+		assert.ContainsOn(a, "abcdef", "cd")
+		assert.NotContainsOn(a, []string{"a"}, "b")
+		a.HasPrefix("foobar", "foo")
+		a.HasNoPrefix("foobar", "bar")
+		a.HasSuffix("foobar", "bar")
+		a.HasNoSuffix("foobar", "foo")
+		a.Panics(func() { panic("panic") })
+		a.NotPanics(func() {})
+		a.PanicIs(func() { panic("panic") }, "panic")
+		assert.GreaterOn(a, 2, 1)
+		assert.SmallerOn(a, "a", "b")
+	})
+	assertSuccess(t, "Zero of nil", func(t testing.TB) {
+		assert.New(t).Zero(nil)
+		assert.Zero[any](t, nil)
+	})
+	assertFailure(t, "NotZero of nil", func(t testing.TB) {
+		assert.New(t).NotZero(nil)
+	})
+	assertFailure(t, "Equal", func(t testing.TB) {
+		assert.EqualOn(assert.New(t), 1, 2)
+	})
+	assertFailure(t, "Nil", func(t testing.TB) {
+		assert.New(t).Nil(errFoo)
+	})
+	assertFailure(t, "Greater", func(t testing.TB) {
+		assert.GreaterOn(assert.New(t), 1.0, 2.0)
+	})
+	assertFailure(t, "Smaller", func(t testing.TB) {
+		assert.SmallerOn(assert.New(t), 2, 1)
+	})
+	seq := func() iter.Seq[int] {
+		return slices.Values([]int{1, 2, 3})
+	}
+	assertSuccess(t, "Contains on iterable", func(t testing.TB) {
+		assert.ContainsOn(assert.New(t), seq(), 3)
+	})
+	assertFailure(t, "Contains on iterable", func(t testing.TB) {
+		assert.ContainsOn(assert.New(t), seq(), 4)
+	})
+	assertFailure(t, "NotContains on string", func(t testing.TB) {
+		assert.NotContainsOn(assert.New(t), "abcdef", "cd")
+	})
+	assertFailure(t, "generic function with the bound testing.TB", func(t testing.TB) {
+		assert.Equal(assert.New(t).TB(), "foo", "bar")
+	})
+}
+
+func TestAssertWithMessage(t *testing.T) {
+	tt := &recordingTester{T: t}
+	a := assert.New(tt).WithMessage("user %d", 7).WithContext("name", "bob", "age", 30)
+	a.True(false)
+
+	if len(tt.fatals) != 1 {
+		t.Fatalf("expected one failure, got %d", len(tt.fatals))
+	}
+	want := "user 7 [name=bob, age=30]: didn't get true"
+	if tt.fatals[0] != want {
+		t.Errorf("got failure %q, want %q", tt.fatals[0], want)
+	}
+
+	assert.Panics(t, func() {
+		a.WithContext("key")
+	})
+}
+
+func TestAssertSoft(t *testing.T) {
+	tt := &recordingTester{T: t}
+	a := assert.New(tt).Soft()
+	assert.EqualOn(a, 1, 2)
+	a.WithContext("step", 2).True(false)
+	a.Nil(nil)
+
+	if len(tt.fatals) != 0 {
+		t.Fatalf("soft assertions shouldn't stop the test, got %v", tt.fatals)
+	}
+	if len(tt.errors) != 0 {
+		t.Fatalf("failures shouldn't be reported before cleanup, got %v", tt.errors)
+	}
+
+	tt.runCleanups()
+
+	if len(tt.errors) != 1 {
+		t.Fatalf("expected one report, got %v", tt.errors)
+	}
+	report := tt.errors[0]
+	for _, want := range []string{"2 assertion(s) failed", "assertions_test.go:", "expected equal values", "[step=2]: didn't get true"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report %q doesn't mention %q", report, want)
+		}
+	}
+}
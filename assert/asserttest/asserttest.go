@@ -0,0 +1,274 @@
+// Package asserttest provides a recording testing.TB to unit test
+// assertions built on top of the assert package.
+package asserttest
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/xandalm/go-testing/internal/caller"
+)
+
+// Call is a call made to the recording TB.
+type Call struct {
+	Method  string
+	Message string
+	File    string
+	Line    int
+}
+
+func (c Call) String() string {
+	return fmt.Sprintf("%s:%d: %s: %s", filepath.Base(c.File), c.Line, c.Method, c.Message)
+}
+
+// TB is a testing.TB that records the calls made to it instead of reporting
+// them to the parent test. Calls that don't report (TempDir, Setenv, ...)
+// go to the parent.
+//
+// FailNow, Fatal, SkipNow and Skip end the running goroutine, so the code
+// under test must be run by Run.
+type TB struct {
+	testing.TB
+
+	mu           sync.Mutex
+	helpers      caller.Helpers
+	calls        []Call
+	failed       bool
+	skipped      bool
+	cleanups     []func()
+	cleanupOrder []int
+}
+
+func NewTB(t testing.TB) *TB {
+	if t == nil {
+		panic("asserttest: nil testing.TB")
+	}
+	return &TB{TB: t}
+}
+
+var pkgPrefix = caller.Package(NewTB)
+
+// Run calls fn with r in a new goroutine and waits for it and for the
+// registered cleanups to finish, including those registered by cleanups.
+// It reports whether fn returned normally, rather than by FailNow, SkipNow
+// or a panic. Panics, of fn or of the cleanups, fail r and are recorded as
+// calls to "Panic".
+func (r *TB) Run(fn func(t testing.TB)) bool {
+	returned := false
+	r.inGoroutine(func() {
+		fn(r)
+		returned = true
+	})
+
+	for {
+		r.mu.Lock()
+		i := len(r.cleanups) - 1
+		if i < 0 {
+			r.mu.Unlock()
+			return returned
+		}
+		cleanup := r.cleanups[i]
+		r.cleanups = r.cleanups[:i]
+		r.cleanupOrder = append(r.cleanupOrder, i)
+		r.mu.Unlock()
+
+		r.inGoroutine(cleanup)
+	}
+}
+
+func (r *TB) inGoroutine(fn func()) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer func() {
+			if p := recover(); p != nil {
+				frame := panicSite()
+				r.mu.Lock()
+				defer r.mu.Unlock()
+				r.calls = append(r.calls, Call{"Panic", fmt.Sprint(p), frame.File, frame.Line})
+				r.failed = true
+			}
+		}()
+		fn()
+	}()
+	<-done
+}
+
+// panicSite returns the frame which panicked, when called by a deferred
+// function recovering the panic.
+func panicSite() runtime.Frame {
+	var pcs [50]uintptr
+	n := runtime.Callers(2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	panicking := false
+	for {
+		frame, more := frames.Next()
+		if panicking && !strings.HasPrefix(frame.Function, "runtime.") {
+			return frame
+		}
+		panicking = panicking || frame.Function == "runtime.gopanic"
+		if !more {
+			return runtime.Frame{}
+		}
+	}
+}
+
+func (r *TB) record(method, msg string) {
+	frame, _ := r.helpers.Frame(2, pkgPrefix)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, Call{method, msg, frame.File, frame.Line})
+}
+
+func (r *TB) Helper() {
+	r.helpers.Mark(1)
+}
+
+func (r *TB) Log(args ...any) {
+	r.record("Log", sprintln(args))
+}
+
+func (r *TB) Logf(format string, args ...any) {
+	r.record("Log", fmt.Sprintf(format, args...))
+}
+
+func (r *TB) Error(args ...any) {
+	r.record("Error", sprintln(args))
+	r.Fail()
+}
+
+func (r *TB) Errorf(format string, args ...any) {
+	r.record("Error", fmt.Sprintf(format, args...))
+	r.Fail()
+}
+
+func (r *TB) Fatal(args ...any) {
+	r.record("Fatal", sprintln(args))
+	r.FailNow()
+}
+
+func (r *TB) Fatalf(format string, args ...any) {
+	r.record("Fatal", fmt.Sprintf(format, args...))
+	r.FailNow()
+}
+
+func (r *TB) Skip(args ...any) {
+	r.record("Skip", sprintln(args))
+	r.SkipNow()
+}
+
+func (r *TB) Skipf(format string, args ...any) {
+	r.record("Skip", fmt.Sprintf(format, args...))
+	r.SkipNow()
+}
+
+func (r *TB) Fail() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failed = true
+}
+
+func (r *TB) FailNow() {
+	r.Fail()
+	runtime.Goexit()
+}
+
+func (r *TB) Failed() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.failed
+}
+
+func (r *TB) SkipNow() {
+	r.mu.Lock()
+	r.skipped = true
+	r.mu.Unlock()
+	runtime.Goexit()
+}
+
+func (r *TB) Skipped() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.skipped
+}
+
+func (r *TB) Cleanup(fn func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cleanups = append(r.cleanups, fn)
+}
+
+// Calls returns the recorded calls to Log, Error, Fatal and Skip (and their
+// formatting variants) in the order they were made.
+func (r *TB) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.calls)
+}
+
+// Messages returns the messages of the recorded calls to method.
+func (r *TB) Messages(method string) []string {
+	var msgs []string
+	for _, c := range r.Calls() {
+		if c.Method == method {
+			msgs = append(msgs, c.Message)
+		}
+	}
+	return msgs
+}
+
+// Helpers returns the names of the functions marked as helpers.
+func (r *TB) Helpers() []string {
+	return r.helpers.Funcs()
+}
+
+// CleanupOrder returns the registration indexes of the cleanups in the
+// order they ran.
+func (r *TB) CleanupOrder() []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.cleanupOrder)
+}
+
+func sprintln(args []any) string {
+	s := fmt.Sprintln(args...)
+	return s[:len(s)-1]
+}
+
+// ExpectFailure runs fn with a recording TB and fails t if fn didn't fail.
+func ExpectFailure(t testing.TB, fn func(t testing.TB)) *TB {
+	t.Helper()
+
+	r := NewTB(t)
+	r.Run(fn)
+	if !r.Failed() {
+		t.Fatal("asserttest: expected failure, but succeeded")
+	}
+	return r
+}
+
+// ExpectSuccess runs fn with a recording TB and fails t if fn failed.
+func ExpectSuccess(t testing.TB, fn func(t testing.TB)) *TB {
+	t.Helper()
+
+	r := NewTB(t)
+	r.Run(fn)
+	if r.Failed() {
+		t.Fatalf("asserttest: expected success, but failed:\n%s", r.report())
+	}
+	return r
+}
+
+func (r *TB) report() string {
+	var b strings.Builder
+	for _, c := range r.Calls() {
+		fmt.Fprintf(&b, "\t%v\n", c)
+	}
+	return b.String()
+}
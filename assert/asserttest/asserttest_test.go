@@ -0,0 +1,167 @@
+package asserttest_test
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/xandalm/go-testing/assert"
+	"github.com/xandalm/go-testing/assert/asserttest"
+)
+
+func assertEven(t testing.TB, n int) {
+	t.Helper()
+	if n%2 != 0 {
+		t.Errorf("%d isn't even", n)
+	}
+}
+
+func TestTB(t *testing.T) {
+	t.Run("records calls", func(t *testing.T) {
+		r := asserttest.NewTB(t)
+		returned := r.Run(func(t testing.TB) {
+			t.Log("hello", "world")
+			assertEven(t, 3)
+			t.Fatalf("stop %d", 1)
+			t.Error("unreachable")
+		})
+
+		if returned {
+			t.Error("Run should report the goroutine was ended by FailNow")
+		}
+		if !r.Failed() {
+			t.Error("should be failed")
+		}
+		calls := r.Calls()
+		if len(calls) != 3 {
+			t.Fatalf("expected 3 calls, got %v", calls)
+		}
+		want := []asserttest.Call{
+			{Method: "Log", Message: "hello world"},
+			{Method: "Error", Message: "3 isn't even"},
+			{Method: "Fatal", Message: "stop 1"},
+		}
+		for i, c := range calls {
+			if c.Method != want[i].Method || c.Message != want[i].Message {
+				t.Errorf("call %d: got %s %q, want %s %q", i, c.Method, c.Message, want[i].Method, want[i].Message)
+			}
+			if filepath.Base(c.File) != "asserttest_test.go" {
+				t.Errorf("call %d: expected to be located in the test, got %s", i, c)
+			}
+		}
+		if calls[1].Line == 0 || calls[1].Line != calls[0].Line+1 {
+			t.Errorf("helper frame should be skipped, got %v", calls[1])
+		}
+		if !slices.ContainsFunc(r.Helpers(), func(name string) bool {
+			return strings.HasSuffix(name, ".assertEven")
+		}) {
+			t.Errorf("assertEven should be marked as helper, got %v", r.Helpers())
+		}
+	})
+	t.Run("skips", func(t *testing.T) {
+		r := asserttest.NewTB(t)
+		r.Run(func(t testing.TB) {
+			t.Skip("not now")
+		})
+		if !r.Skipped() || r.Failed() {
+			t.Error("should be skipped and not failed")
+		}
+		if msgs := r.Messages("Skip"); len(msgs) != 1 || msgs[0] != "not now" {
+			t.Errorf("unexpected skip messages %v", msgs)
+		}
+	})
+	t.Run("runs cleanups in reverse order", func(t *testing.T) {
+		r := asserttest.NewTB(t)
+		var ran []int
+		r.Run(func(t testing.TB) {
+			for i := range 3 {
+				t.Cleanup(func() {
+					ran = append(ran, i)
+					if i == 1 {
+						t.FailNow()
+					}
+				})
+			}
+		})
+		if !slices.Equal(ran, []int{2, 1, 0}) {
+			t.Errorf("cleanups ran as %v", ran)
+		}
+		if got := r.CleanupOrder(); !slices.Equal(got, []int{2, 1, 0}) {
+			t.Errorf("recorded cleanup order %v", got)
+		}
+		if !r.Failed() {
+			t.Error("failure in cleanup should be recorded")
+		}
+	})
+	t.Run("runs cleanups registered by cleanups", func(t *testing.T) {
+		r := asserttest.NewTB(t)
+		var ran []string
+		r.Run(func(t testing.TB) {
+			t.Cleanup(func() { ran = append(ran, "first") })
+			t.Cleanup(func() {
+				ran = append(ran, "second")
+				t.Cleanup(func() { ran = append(ran, "nested") })
+			})
+		})
+		if !slices.Equal(ran, []string{"second", "nested", "first"}) {
+			t.Errorf("cleanups ran as %v", ran)
+		}
+	})
+	t.Run("records panics", func(t *testing.T) {
+		r := asserttest.NewTB(t)
+		cleaned := false
+		returned := r.Run(func(t testing.TB) {
+			t.Cleanup(func() {
+				cleaned = true
+				panic("in cleanup")
+			})
+			var m map[string]int
+			m["a"] = 1
+		})
+
+		if returned || !r.Failed() || !cleaned {
+			t.Errorf("panic should fail, returned %v, failed %v, cleaned %v", returned, r.Failed(), cleaned)
+		}
+		want := []string{"assignment to entry in nil map", "in cleanup"}
+		if got := r.Messages("Panic"); !slices.Equal(got, want) {
+			t.Errorf("got panics %q, want %q", got, want)
+		}
+		for _, c := range r.Calls() {
+			if filepath.Base(c.File) != "asserttest_test.go" {
+				t.Errorf("panic should be located in the test, got %s", c)
+			}
+		}
+	})
+}
+
+func TestExpect(t *testing.T) {
+	r := asserttest.ExpectFailure(t, func(t testing.TB) {
+		assert.Equal(t, 1, 2)
+	})
+	if msgs := r.Messages("Fatal"); len(msgs) != 1 {
+		t.Errorf("expected one fatal message, got %v", msgs)
+	}
+
+	asserttest.ExpectSuccess(t, func(t testing.TB) {
+		assert.Equal(t, 1, 1)
+	})
+
+	outer := asserttest.NewTB(t)
+	outer.Run(func(t testing.TB) {
+		asserttest.ExpectFailure(t, func(t testing.TB) {})
+	})
+	if !outer.Failed() {
+		t.Error("ExpectFailure should fail when the function succeeds")
+	}
+
+	outer = asserttest.NewTB(t)
+	outer.Run(func(t testing.TB) {
+		asserttest.ExpectSuccess(t, func(t testing.TB) {
+			t.Error("failure")
+		})
+	})
+	if !outer.Failed() {
+		t.Error("ExpectSuccess should fail when the function fails")
+	}
+}
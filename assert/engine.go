@@ -0,0 +1,500 @@
+package assert
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+// maxDiffs is the number of differences listed in a failure message.
+const maxDiffs = 10
+
+// comparer is the comparison engine behind the assertions. It walks two
+// values deeply recording their differences, keeping track of the visited
+// references so cyclic values are compared in finite time.
+type comparer struct {
+	opts    equalOptions
+	diffs   []difference
+	visited map[visit]bool
+	path    []step
+}
+
+// step is a step of the path from the compared values to the ones being
+// compared: a struct field or transformation name, a slice index or a map
+// key.
+type step struct {
+	name  string
+	index int
+	key   reflect.Value
+}
+
+func (s step) String() string {
+	switch {
+	case s.name != "":
+		return "." + s.name
+	case s.key.IsValid():
+		return "[" + formatValue(s.key) + "]"
+	default:
+		return fmt.Sprintf("[%d]", s.index)
+	}
+}
+
+func (c *comparer) push(s step) {
+	c.path = append(c.path, s)
+}
+
+func (c *comparer) pop() {
+	c.path = c.path[:len(c.path)-1]
+}
+
+func (c *comparer) pathString() string {
+	var b strings.Builder
+	for _, s := range c.path {
+		b.WriteString(s.String())
+	}
+	return b.String()
+}
+
+// visit is a pair of references being compared. Once visited, a pair is
+// considered equal, as any difference below it is found by the first visit.
+type visit struct {
+	a, b unsafe.Pointer
+	len  int
+	typ  reflect.Type
+}
+
+// kindHandler compares values of the same type and kind.
+type kindHandler func(c *comparer, a, b reflect.Value) bool
+
+var kindHandlers map[reflect.Kind]kindHandler
+
+func init() {
+	kindHandlers = map[reflect.Kind]kindHandler{
+		reflect.Pointer:   (*comparer).equalPointer,
+		reflect.Interface: (*comparer).equalInterface,
+		reflect.Struct:    (*comparer).equalStruct,
+		reflect.Slice:     (*comparer).equalSlice,
+		reflect.Array:     (*comparer).equalElems,
+		reflect.Map:       (*comparer).equalMap,
+		reflect.Func:      (*comparer).equalFunc,
+	}
+}
+
+func newComparer(opts []Option) *comparer {
+	c := &comparer{visited: map[visit]bool{}}
+	for _, opt := range opts {
+		opt(&c.opts)
+	}
+	return c
+}
+
+// compare walks a and b, returning the differences found.
+func compare[T any](a, b T, opts []Option) []difference {
+	c := newComparer(opts)
+	c.equal(rootValue(a), rootValue(b), nil)
+	return c.diffs
+}
+
+// rootValue returns v as an addressable reflect.Value, so that unexported
+// fields in it can be read by accessible.
+func rootValue[T any](v T) reflect.Value {
+	return reflect.ValueOf(&v).Elem()
+}
+
+// accessible returns v without the read-only restriction of values
+// obtained through unexported fields, so comparers, transformers and Equal
+// methods can be called with them.
+func accessible(v reflect.Value) reflect.Value {
+	if !v.IsValid() || v.CanInterface() || !v.CanAddr() {
+		return v
+	}
+	return reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem()
+}
+
+// addressable returns an addressable copy of v, when v isn't one.
+func addressable(v reflect.Value) reflect.Value {
+	if v.CanAddr() {
+		return v
+	}
+	c := reflect.New(v.Type()).Elem()
+	c.Set(v)
+	return c
+}
+
+func (c *comparer) report(a, b reflect.Value) bool {
+	c.diffs = append(c.diffs, difference{path: c.pathString(), a: a, b: b})
+	return false
+}
+
+// equal compares a and b, recording their differences. The skip
+// transformer isn't applied to a and b, as they are its own results.
+func (c *comparer) equal(a, b reflect.Value, skip *transformer) bool {
+	a, b = accessible(a), accessible(b)
+	if !a.IsValid() || !b.IsValid() {
+		if a.IsValid() != b.IsValid() {
+			return c.report(a, b)
+		}
+		return true
+	}
+	if a.Type() != b.Type() {
+		return c.report(a, b)
+	}
+
+	typ := a.Type()
+	if eq, ok := c.opts.comparers[typ]; ok {
+		if !eq(a, b) {
+			return c.report(a, b)
+		}
+		return true
+	}
+	if tr, ok := c.opts.transformers[typ]; ok && tr != skip {
+		c.push(step{name: tr.name + "()"})
+		defer c.pop()
+		return c.equal(tr.fn(a), tr.fn(b), tr)
+	}
+	if !c.opts.ignoreEqualMethods {
+		if eq, ok := equalMethod(a, b); ok {
+			if !eq {
+				return c.report(a, b)
+			}
+			return true
+		}
+	}
+
+	if v, ok := visitOf(a, b); ok {
+		if c.visited[v] {
+			return true
+		}
+		c.visited[v] = true
+	}
+	if handler, ok := kindHandlers[a.Kind()]; ok {
+		if c.opts.maxDepth > 0 && len(c.path) >= c.opts.maxDepth {
+			note := fmt.Sprintf("max depth %d exceeded", c.opts.maxDepth)
+			c.diffs = append(c.diffs, difference{c.pathString(), a, b, note})
+			return false
+		}
+		return handler(c, a, b)
+	}
+	return c.equalScalar(a, b)
+}
+
+// visitOf returns the visit comparing a and b, if they are references.
+func visitOf(a, b reflect.Value) (visit, bool) {
+	switch a.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice:
+		if a.IsNil() || b.IsNil() {
+			return visit{}, false
+		}
+		v := visit{a.UnsafePointer(), b.UnsafePointer(), 0, a.Type()}
+		if a.Kind() == reflect.Slice {
+			v.len = a.Len()
+		}
+		return v, true
+	default:
+		return visit{}, false
+	}
+}
+
+func (c *comparer) equalScalar(a, b reflect.Value) bool {
+	if !a.Equal(b) {
+		return c.report(a, b)
+	}
+	return true
+}
+
+func (c *comparer) equalPointer(a, b reflect.Value) bool {
+	if a.Pointer() == b.Pointer() {
+		return true
+	}
+	if a.IsNil() || b.IsNil() {
+		return c.report(a, b)
+	}
+	return c.equal(a.Elem(), b.Elem(), nil)
+}
+
+func (c *comparer) equalInterface(a, b reflect.Value) bool {
+	if a.IsNil() || b.IsNil() {
+		if a.IsNil() != b.IsNil() {
+			return c.report(a, b)
+		}
+		return true
+	}
+	return c.equal(a.Elem(), b.Elem(), nil)
+}
+
+func (c *comparer) equalStruct(a, b reflect.Value) bool {
+	a, b = addressable(a), addressable(b)
+	typ := a.Type()
+	eq := true
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		if c.opts.ignoreFields[field.Name] || (c.opts.ignoreUnexported && !field.IsExported()) {
+			continue
+		}
+		c.push(step{name: field.Name})
+		if !c.equal(a.Field(i), b.Field(i), nil) {
+			eq = false
+		}
+		c.pop()
+	}
+	return eq
+}
+
+func (c *comparer) equalSlice(a, b reflect.Value) bool {
+	if c.opts.equateEmpty && a.Len() == 0 && b.Len() == 0 {
+		return true
+	}
+	if a.IsNil() != b.IsNil() {
+		return c.report(a, b)
+	}
+	if less, ok := c.opts.sorters[a.Type().Elem()]; ok {
+		a, b = sortedCopy(a, less), sortedCopy(b, less)
+	}
+	return c.equalElems(a, b)
+}
+
+func (c *comparer) equalElems(a, b reflect.Value) bool {
+	eq := true
+	for i := range max(a.Len(), b.Len()) {
+		var va, vb reflect.Value
+		if i < a.Len() {
+			va = a.Index(i)
+		}
+		if i < b.Len() {
+			vb = b.Index(i)
+		}
+		c.push(step{index: i})
+		if !c.equal(va, vb, nil) {
+			eq = false
+		}
+		c.pop()
+	}
+	return eq
+}
+
+func (c *comparer) equalMap(a, b reflect.Value) bool {
+	if c.opts.equateEmpty && a.Len() == 0 && b.Len() == 0 {
+		return true
+	}
+	if a.IsNil() != b.IsNil() {
+		return c.report(a, b)
+	}
+	eq := true
+	for _, k := range sortedKeys(a, b) {
+		va, vb := a.MapIndex(k), b.MapIndex(k)
+		if va.IsValid() {
+			va = addressable(va)
+		}
+		if vb.IsValid() {
+			vb = addressable(vb)
+		}
+		c.push(step{key: k})
+		if !c.equal(va, vb, nil) {
+			eq = false
+		}
+		c.pop()
+	}
+	return eq
+}
+
+func (c *comparer) equalFunc(a, b reflect.Value) bool {
+	if a.Pointer() != b.Pointer() {
+		return c.report(a, b)
+	}
+	return true
+}
+
+// empty reports whether v is nil, has no elements or is the zero value of
+// its type, following pointers.
+func (c *comparer) empty(v reflect.Value) bool {
+	if !v.IsValid() {
+		return true
+	}
+	switch v.Kind() {
+	case reflect.Array, reflect.Chan, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return true
+		}
+		if vis, ok := visitOf(v, v); ok {
+			if c.visited[vis] {
+				// a value referencing itself isn't empty
+				return false
+			}
+			c.visited[vis] = true
+		}
+		return c.empty(v.Elem())
+	case reflect.Func, reflect.UnsafePointer:
+		return v.IsNil()
+	default:
+		return c.equal(v, reflect.Zero(v.Type()), nil)
+	}
+}
+
+var boolType = reflect.TypeFor[bool]()
+
+// equalShape tells how the Equal method of a type is called.
+type equalShape int
+
+const (
+	noEqual equalShape = iota
+	valueEqual
+	addrEqual
+	addrEqualAddr
+)
+
+// equalShapes caches the equalShape of the types compared so far.
+var equalShapes sync.Map
+
+func equalShapeOf(typ reflect.Type) equalShape {
+	if shape, ok := equalShapes.Load(typ); ok {
+		return shape.(equalShape)
+	}
+	isEqualMethod := func(m reflect.Method, arg reflect.Type) bool {
+		mt := m.Type
+		return mt.NumIn() == 2 && mt.NumOut() == 1 && mt.Out(0) == boolType && arg.AssignableTo(mt.In(1))
+	}
+	shape := noEqual
+	if m, ok := typ.MethodByName("Equal"); ok && isEqualMethod(m, typ) {
+		shape = valueEqual
+	} else if typ.Kind() != reflect.Pointer && typ.Kind() != reflect.Interface {
+		ptr := reflect.PointerTo(typ)
+		if m, ok := ptr.MethodByName("Equal"); ok {
+			if isEqualMethod(m, typ) {
+				shape = addrEqual
+			} else if isEqualMethod(m, ptr) && m.Type.In(1) == ptr {
+				shape = addrEqualAddr
+			}
+		}
+	}
+	equalShapes.Store(typ, shape)
+	return shape
+}
+
+// equalMethod calls the Equal method of a with b, when a has one of the
+// shapes recognized by EqualOpts.
+func equalMethod(a, b reflect.Value) (eq, ok bool) {
+	switch a.Kind() {
+	case reflect.Interface:
+		return false, false
+	case reflect.Pointer:
+		if a.IsNil() || b.IsNil() {
+			return false, false
+		}
+	}
+	var args [1]reflect.Value
+	switch equalShapeOf(a.Type()) {
+	case valueEqual:
+		args[0] = b
+	case addrEqual:
+		a, args[0] = addressable(a).Addr(), b
+	case addrEqualAddr:
+		a, args[0] = addressable(a).Addr(), addressable(b).Addr()
+	default:
+		return false, false
+	}
+	return a.MethodByName("Equal").Call(args[:])[0].Bool(), true
+}
+
+func sortedCopy(v reflect.Value, less func(a, b reflect.Value) bool) reflect.Value {
+	c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+	reflect.Copy(c, v)
+	elems := make([]reflect.Value, c.Len())
+	for i := range elems {
+		elems[i] = accessible(c.Index(i))
+	}
+	slices.SortStableFunc(elems, func(x, y reflect.Value) int {
+		switch {
+		case less(x, y):
+			return -1
+		case less(y, x):
+			return 1
+		default:
+			return 0
+		}
+	})
+	sorted := reflect.MakeSlice(v.Type(), len(elems), len(elems))
+	for i, e := range elems {
+		sorted.Index(i).Set(e)
+	}
+	return sorted
+}
+
+// sortedKeys returns the union of the keys of the maps a and b, in the
+// order they are printed.
+func sortedKeys(a, b reflect.Value) []reflect.Value {
+	keys := a.MapKeys()
+	for _, k := range b.MapKeys() {
+		if !a.MapIndex(k).IsValid() {
+			keys = append(keys, k)
+		}
+	}
+	switch a.Type().Key().Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.String:
+		slices.SortFunc(keys, func(x, y reflect.Value) int {
+			return compareKeys(x, y, "", "")
+		})
+		return keys
+	}
+
+	texts := make([]string, len(keys))
+	order := make([]int, len(keys))
+	for i, k := range keys {
+		texts[i], order[i] = formatValue(k), i
+	}
+	slices.SortFunc(order, func(i, j int) int {
+		return compareKeys(keys[i], keys[j], texts[i], texts[j])
+	})
+	sorted := make([]reflect.Value, len(keys))
+	for i, j := range order {
+		sorted[i] = keys[j]
+	}
+	return sorted
+}
+
+// difference is a pair of values found different at path. The note, when
+// set, explains why they were reported.
+type difference struct {
+	path string
+	a, b reflect.Value
+	note string
+}
+
+func (d difference) String() string {
+	path := d.path
+	if path == "" {
+		path = "value"
+	}
+	if d.note != "" {
+		return path + ": " + d.note
+	}
+	return fmt.Sprintf("%s: %s != %s", path, formatValue(d.a), formatValue(d.b))
+}
+
+func formatValue(v reflect.Value) string {
+	if !v.IsValid() {
+		return "<missing>"
+	}
+	f := formatter{visiting: map[visit]bool{}}
+	return f.format(v, true)
+}
+
+// formatDiffs renders the differences one per line, each starting with a
+// newline and a tab.
+func formatDiffs(diffs []difference) string {
+	var b strings.Builder
+	for i, d := range diffs {
+		if i == maxDiffs {
+			fmt.Fprintf(&b, "\n\t... %d more", len(diffs)-maxDiffs)
+			break
+		}
+		b.WriteString("\n\t" + strings.ReplaceAll(d.String(), "\n", "\n\t"))
+	}
+	return b.String()
+}
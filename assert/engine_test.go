@@ -0,0 +1,127 @@
+package assert_test
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xandalm/go-testing/assert"
+)
+
+type graphNode struct {
+	ID    int
+	Edges []*graphNode
+}
+
+// ring returns a cyclic graph of n nodes, each one linked to the next two.
+func ring(n int) *graphNode {
+	nodes := make([]*graphNode, n)
+	for i := range nodes {
+		nodes[i] = &graphNode{ID: i}
+	}
+	for i, node := range nodes {
+		node.Edges = []*graphNode{nodes[(i+1)%n], nodes[(i+2)%n]}
+	}
+	return nodes[0]
+}
+
+func TestEngineCycles(t *testing.T) {
+	assertSuccess(t, "equal cyclic graphs", func(t testing.TB) {
+		assert.Equal(t, ring(10), ring(10))
+	})
+	assertFailure(t, "different cyclic graphs", func(t testing.TB) {
+		a, b := ring(10), ring(10)
+		b.Edges[0].Edges[1].ID = -1
+		assert.Equal(t, a, b)
+	})
+	assertSuccess(t, "self-referential map", func(t testing.TB) {
+		a, b := map[string]any{}, map[string]any{}
+		a["self"], b["self"] = a, b
+		assert.Equal(t, a, b)
+	})
+	assertSuccess(t, "self-referential pointer isn't empty", func(t testing.TB) {
+		p := new(any)
+		*p = p
+		assert.NotEmpty(t, p)
+	})
+	assertSuccess(t, "pointer to empty value is empty", func(t testing.TB) {
+		s := ""
+		ps := &s
+		assert.Empty(t, &ps)
+	})
+}
+
+func TestEngineMaxDepth(t *testing.T) {
+	nested := func(leaf int) any {
+		return [][][]int{{{leaf}}}
+	}
+	assertSuccess(t, "within max depth", func(t testing.TB) {
+		assert.EqualOpts(t, nested(1), nested(1), assert.MaxDepth(3))
+	})
+	got := failure(t, func(t testing.TB) {
+		assert.EqualOpts(t, nested(1), nested(1), assert.MaxDepth(2))
+	})
+	if !strings.Contains(got, "[0][0]: max depth 2 exceeded") {
+		t.Errorf("unexpected message %q", got)
+	}
+	assert.Panics(t, func() {
+		assert.MaxDepth(0)
+	})
+}
+
+func TestEngineShared(t *testing.T) {
+	assertSuccess(t, "Contains uses Equal methods", func(t testing.TB) {
+		assert.Contains(t, []decimal{{1, 0}, {2, 0}}, decimal{20, 1})
+	})
+	assertSuccess(t, "Empty doesn't use Equal methods", func(t testing.TB) {
+		assert.NotEmpty(t, money{0, "USD"})
+		assert.Empty(t, money{})
+	})
+	got := failure(t, func(t testing.TB) {
+		assert.Equal(t, pointer{1, 2}, pointer{1, 3})
+	})
+	want := `expected equal values, but got assert_test.pointer{X: 1, Y: 2} and assert_test.pointer{X: 1, Y: 3}
+differences:
+	.Y: 2 != 3`
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func BenchmarkCompare(b *testing.B) {
+	graphs := map[string]func() any{
+		"ring": func() any { return ring(10_000) },
+		"maps": func() any {
+			m := map[string][]int{}
+			for i := range 10_000 {
+				m[fmt.Sprint(i)] = []int{i, i + 1, i + 2}
+			}
+			return m
+		},
+		"structs": func() any {
+			s := make([]account, 10_000)
+			for i := range s {
+				s[i] = account{ID: i, Name: fmt.Sprint(i), Tags: []string{"a", "b"}}
+			}
+			return s
+		},
+	}
+	for name, gen := range graphs {
+		x, y := gen(), gen()
+		b.Run(name+"/engine", func(b *testing.B) {
+			for range b.N {
+				if !assert.Equivalent(x, y) {
+					b.Fatal("should be equal")
+				}
+			}
+		})
+		b.Run(name+"/reflect.DeepEqual", func(b *testing.B) {
+			for range b.N {
+				if !reflect.DeepEqual(x, y) {
+					b.Fatal("should be equal")
+				}
+			}
+		})
+	}
+}
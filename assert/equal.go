@@ -0,0 +1,168 @@
+package assert
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// Option customizes how EqualOpts and NotEqualOpts compare values, or
+// report their failure.
+type Option func(*equalOptions)
+
+type equalOptions struct {
+	ignoreFields       map[string]bool
+	ignoreUnexported   bool
+	ignoreEqualMethods bool
+	maxDepth           int
+	equateEmpty        bool
+	sorters            map[reflect.Type]func(a, b reflect.Value) bool
+	comparers          map[reflect.Type]func(a, b reflect.Value) bool
+	transformers       map[reflect.Type]*transformer
+	message            []any
+}
+
+type transformer struct {
+	name string
+	fn   func(v reflect.Value) reflect.Value
+}
+
+// IgnoreFields ignores the struct fields with the given names, in any
+// struct type.
+func IgnoreFields(names ...string) Option {
+	return func(o *equalOptions) {
+		if o.ignoreFields == nil {
+			o.ignoreFields = map[string]bool{}
+		}
+		for _, name := range names {
+			o.ignoreFields[name] = true
+		}
+	}
+}
+
+// IgnoreUnexported ignores the unexported fields of any struct type.
+func IgnoreUnexported() Option {
+	return func(o *equalOptions) {
+		o.ignoreUnexported = true
+	}
+}
+
+// IgnoreEqualMethods compares values by walking them even when they have an
+// Equal method.
+func IgnoreEqualMethods() Option {
+	return func(o *equalOptions) {
+		o.ignoreEqualMethods = true
+	}
+}
+
+// MaxDepth stops walking the values deeper than n levels, reporting the
+// values found there as different.
+func MaxDepth(n int) Option {
+	if n <= 0 {
+		panic("assert: max depth must be positive")
+	}
+	return func(o *equalOptions) {
+		o.maxDepth = n
+	}
+}
+
+// EquateEmpty considers nil and empty slices (or maps) of the same type
+// equal.
+func EquateEmpty() Option {
+	return func(o *equalOptions) {
+		o.equateEmpty = true
+	}
+}
+
+// SortSlices sorts copies of the []T values using less before comparing
+// them, making the comparison independent of the elements order.
+func SortSlices[T any](less func(a, b T) bool) Option {
+	return func(o *equalOptions) {
+		if o.sorters == nil {
+			o.sorters = map[reflect.Type]func(a, b reflect.Value) bool{}
+		}
+		o.sorters[reflect.TypeFor[T]()] = func(a, b reflect.Value) bool {
+			return less(a.Interface().(T), b.Interface().(T))
+		}
+	}
+}
+
+// Comparer compares the values of type T using eq instead of walking them.
+func Comparer[T any](eq func(a, b T) bool) Option {
+	return func(o *equalOptions) {
+		if o.comparers == nil {
+			o.comparers = map[reflect.Type]func(a, b reflect.Value) bool{}
+		}
+		o.comparers[reflect.TypeFor[T]()] = func(a, b reflect.Value) bool {
+			return eq(a.Interface().(T), b.Interface().(T))
+		}
+	}
+}
+
+// Transformer compares the values of type T by comparing their results of
+// fn. The name identifies the transformation in failure messages.
+func Transformer[T, R any](name string, fn func(T) R) Option {
+	return func(o *equalOptions) {
+		if o.transformers == nil {
+			o.transformers = map[reflect.Type]*transformer{}
+		}
+		o.transformers[reflect.TypeFor[T]()] = &transformer{name, func(v reflect.Value) reflect.Value {
+			r := fn(v.Interface().(T))
+			return reflect.ValueOf(&r).Elem()
+		}}
+	}
+}
+
+// Equivalent reports whether a and b are equal as EqualOpts compares them,
+// for matching values as the assertions do, e.g. the arguments of calls.
+func Equivalent[T any](a, b T, opts ...Option) bool {
+	return len(compare(a, b, opts)) == 0
+}
+
+// Message replaces the failure message, formatted as by fmt.Sprintf, as
+// the out arguments of the other assertions do.
+func Message(format string, args ...any) Option {
+	return func(o *equalOptions) {
+		o.message = append([]any{format}, args...)
+	}
+}
+
+// message returns the message given in opts, as out arguments.
+func message(opts []Option) []any {
+	var o equalOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o.message
+}
+
+// EqualOpts compares a and b deeply, customized by opts.
+//
+// Unless IgnoreEqualMethods is given, a value v of type T found at any depth
+// is compared with w by calling, when T or *T has one of them, the methods:
+//
+//	func (T) Equal(U) bool   // T assignable to U
+//	func (*T) Equal(U) bool  // T assignable to U
+//	func (*T) Equal(*T) bool
+//
+// as with time.Time. Comparers and transformers given in opts take
+// precedence over Equal methods.
+//
+//	assert.EqualOpts(t, got, want, assert.IgnoreFields("ID"), assert.Message("user %d", id))
+func EqualOpts[T any](t testing.TB, a, b T, opts ...Option) {
+	t.Helper()
+
+	if diffs := compare(a, b, opts); len(diffs) > 0 {
+		f := Failure{Expected: format(b), Actual: format(a), Diff: formatDiffs(diffs)}
+		f.Message = "expected equal values"
+		report(t, f, message(opts))
+	}
+}
+
+func NotEqualOpts[T any](t testing.TB, a, b T, opts ...Option) {
+	t.Helper()
+
+	if diffs := compare(a, b, opts); len(diffs) == 0 {
+		output(t, fmt.Sprintf("expected different values, but %s is equal to %s", format(a), format(b)), message(opts))
+	}
+}
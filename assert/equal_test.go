@@ -0,0 +1,205 @@
+package assert_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xandalm/go-testing/assert"
+)
+
+type account struct {
+	ID        int
+	Name      string
+	Tags      []string
+	Meta      map[string]string
+	UpdatedAt time.Time
+	secret    string
+}
+
+func TestEqualOpts(t *testing.T) {
+	a := account{ID: 1, Name: "foo", Tags: []string{"a", "b"}, UpdatedAt: time.Now(), secret: "x"}
+	b := account{ID: 2, Name: "foo", Tags: []string{"a", "b"}, UpdatedAt: time.Now().Add(time.Hour), secret: "y"}
+
+	assertSuccess(t, "same values", func(t testing.TB) {
+		assert.EqualOpts(t, a, a)
+	})
+	assertFailure(t, "different values", func(t testing.TB) {
+		assert.EqualOpts(t, a, b)
+	})
+	assertFailure(t, "ignoring some of the different fields", func(t testing.TB) {
+		assert.EqualOpts(t, a, b, assert.IgnoreFields("ID", "UpdatedAt"))
+	})
+	assertSuccess(t, "ignoring every different field", func(t testing.TB) {
+		assert.EqualOpts(t, a, b, assert.IgnoreFields("ID", "UpdatedAt"), assert.IgnoreUnexported())
+	})
+	assertSuccess(t, "ignoring fields through pointers", func(t testing.TB) {
+		assert.EqualOpts(t, &a, &b, assert.IgnoreFields("ID", "UpdatedAt", "secret"))
+	})
+	assertFailure(t, "nil and empty", func(t testing.TB) {
+		assert.EqualOpts(t, account{Meta: map[string]string{}}, account{})
+	})
+	assertSuccess(t, "nil and empty equated", func(t testing.TB) {
+		assert.EqualOpts(t, account{Tags: []string{}, Meta: map[string]string{}}, account{}, assert.EquateEmpty())
+	})
+	assertSuccess(t, "sorted slices", func(t testing.TB) {
+		assert.EqualOpts(t, []int{3, 1, 2}, []int{1, 2, 3}, assert.SortSlices(func(a, b int) bool {
+			return a < b
+		}))
+	})
+	assertSuccess(t, "comparer", func(t testing.TB) {
+		assert.EqualOpts(t, a, b,
+			assert.IgnoreFields("ID"),
+			assert.IgnoreUnexported(),
+			assert.Comparer(func(a, b time.Time) bool {
+				return a.Sub(b).Abs() <= 2*time.Hour
+			}))
+	})
+	assertSuccess(t, "comparer on unexported fields", func(t testing.TB) {
+		assert.EqualOpts(t, a, b,
+			assert.IgnoreFields("ID", "UpdatedAt"),
+			assert.Comparer(func(a, b string) bool {
+				return len(a) == len(b)
+			}))
+	})
+	assertSuccess(t, "transformer", func(t testing.TB) {
+		assert.EqualOpts(t, []string{"Foo", "BAR"}, []string{"foo", "bar"}, assert.Transformer("ToLower", strings.ToLower))
+	})
+	assertFailure(t, "transformer", func(t testing.TB) {
+		assert.EqualOpts(t, []string{"Foo", "BAR"}, []string{"foo", "baz"}, assert.Transformer("ToLower", strings.ToLower))
+	})
+	assertSuccess(t, "not equal", func(t testing.TB) {
+		assert.NotEqualOpts(t, a, b, assert.IgnoreFields("ID"))
+	})
+	assertFailure(t, "not equal", func(t testing.TB) {
+		assert.NotEqualOpts(t, a, b, assert.IgnoreFields("ID", "UpdatedAt"), assert.IgnoreUnexported())
+	})
+}
+
+func TestEqualOptsMessage(t *testing.T) {
+	a := account{ID: 1, Tags: []string{"a"}, Meta: map[string]string{"k": "v"}}
+	b := account{ID: 2, Tags: []string{"a", "b"}, Meta: map[string]string{"k": "w"}}
+	got := failure(t, func(t testing.TB) {
+		assert.EqualOpts(t, a, b)
+	})
+	want := `expected equal values
+differences:
+	.ID: 1 != 2
+	.Tags[1]: <missing> != "b"
+	.Meta["k"]: "v" != "w"`
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+
+	got = failure(t, func(t testing.TB) {
+		assert.EqualOpts(t, a, b, assert.IgnoreFields("Tags"), assert.Message("account %d", 1))
+	})
+	if got != "account 1\ndifferences:\n\t.ID: 1 != 2\n\t.Meta[\"k\"]: \"v\" != \"w\"" {
+		t.Errorf("unexpected message %q", got)
+	}
+	got = failure(t, func(t testing.TB) {
+		assert.NotEqualOpts(t, a, a, assert.Message("account %d", 1))
+	})
+	if got != "account 1" {
+		t.Errorf("unexpected message %q", got)
+	}
+}
+
+type money struct {
+	cents    int64
+	currency string
+}
+
+// Equal considers the zero amount equal in any currency.
+func (m money) Equal(o money) bool {
+	if m.cents == 0 && o.cents == 0 {
+		return true
+	}
+	return m == o
+}
+
+type decimal struct {
+	unscaled int64
+	scale    int
+}
+
+func (d *decimal) Equal(o *decimal) bool {
+	if d.scale < o.scale {
+		return (&decimal{d.unscaled * 10, d.scale + 1}).Equal(o)
+	}
+	if o.scale < d.scale {
+		return d.Equal(&decimal{o.unscaled * 10, o.scale + 1})
+	}
+	return d.unscaled == o.unscaled
+}
+
+type invoice struct {
+	Total   money
+	Rates   []decimal
+	Issued  time.Time
+	ByMonth map[string]decimal
+}
+
+func TestEqualMethods(t *testing.T) {
+	now := time.Now()
+	a := invoice{
+		Total:   money{0, "USD"},
+		Rates:   []decimal{{15, 1}},
+		Issued:  now,
+		ByMonth: map[string]decimal{"jan": {100, 2}},
+	}
+	b := invoice{
+		Total:   money{0, "EUR"},
+		Rates:   []decimal{{150, 2}},
+		Issued:  now.In(time.FixedZone("UTC-3", -3*60*60)),
+		ByMonth: map[string]decimal{"jan": {1, 0}},
+	}
+
+	assertSuccess(t, "value receiver", func(t testing.TB) {
+		assert.Equal(t, money{0, "USD"}, money{0, "BRL"})
+	})
+	assertFailure(t, "value receiver", func(t testing.TB) {
+		assert.Equal(t, money{1, "USD"}, money{1, "BRL"})
+	})
+	assertSuccess(t, "pointer receiver and argument", func(t testing.TB) {
+		assert.Equal(t, &decimal{15, 1}, &decimal{150, 2})
+	})
+	assertSuccess(t, "pointer receiver on addressable value", func(t testing.TB) {
+		assert.Equal(t, decimal{15, 1}, decimal{150, 2})
+	})
+	assertSuccess(t, "time.Time", func(t testing.TB) {
+		assert.Equal(t, now, now.UTC())
+	})
+	assertSuccess(t, "at any depth", func(t testing.TB) {
+		assert.Equal(t, a, b)
+	})
+	assertFailure(t, "opted out", func(t testing.TB) {
+		assert.EqualOpts(t, a, b, assert.IgnoreEqualMethods())
+	})
+	assertSuccess(t, "comparers take precedence", func(t testing.TB) {
+		assert.EqualOpts(t, money{1, "USD"}, money{2, "USD"}, assert.Comparer(func(a, b money) bool {
+			return a.currency == b.currency
+		}))
+	})
+}
+
+type handler struct {
+	Name string
+	Fn   func() int
+}
+
+func TestEqualFuncs(t *testing.T) {
+	one := func() int { return 1 }
+	two := func() int { return 2 }
+
+	assertSuccess(t, "same nested function", func(t testing.TB) {
+		assert.Equal(t, handler{"a", one}, handler{"a", one})
+		assert.Equal(t, handler{"a", nil}, handler{"a", nil})
+	})
+	assertFailure(t, "different nested functions", func(t testing.TB) {
+		assert.Equal(t, handler{"a", one}, handler{"a", two})
+	})
+	assertFailure(t, "nested function and nil", func(t testing.TB) {
+		assert.Equal(t, handler{"a", one}, handler{"a", nil})
+	})
+}
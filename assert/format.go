@@ -0,0 +1,282 @@
+package assert
+
+import (
+	"cmp"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// maxLineWidth is the width up to which composite values are kept on
+	// a single line.
+	maxLineWidth = 80
+	// maxElems is the number of elements printed for arrays, slices and
+	// maps, and maxBytes the number of bytes hex dumped for byte slices.
+	maxElems = 32
+	maxBytes = 256
+)
+
+var (
+	errorType    = reflect.TypeFor[error]()
+	stringerType = reflect.TypeFor[fmt.Stringer]()
+	timeType     = reflect.TypeFor[time.Time]()
+)
+
+// Format returns the Go-syntax-like representation of v shown in failure
+// messages.
+//
+// Pointers are followed, printing the cyclic references through pointers,
+// maps or slices as <cycle>. Map keys are sorted, byte slices are hex
+// dumped, long collections are cut, and errors, fmt.Stringer values and
+// time.Time print as their text, even in unexported fields.
+func Format(v any) string {
+	return format(v)
+}
+
+func format(v any) string {
+	f := formatter{visiting: map[visit]bool{}}
+	return f.format(reflect.ValueOf(v), true)
+}
+
+type formatter struct {
+	// visiting are the references being formatted, by type, address and
+	// length for slices, so a pointer to a struct and to its first field
+	// aren't mistaken for each other.
+	visiting map[visit]bool
+}
+
+// enter marks the reference v as being formatted, unless it already is,
+// in a cycle.
+func (f *formatter) enter(v reflect.Value) (vis visit, ok bool) {
+	vis, _ = visitOf(v, v)
+	if f.visiting[vis] {
+		return vis, false
+	}
+	f.visiting[vis] = true
+	return vis, true
+}
+
+func (f *formatter) format(v reflect.Value, showType bool) string {
+	if !v.IsValid() {
+		return "nil"
+	}
+	// The values in unexported fields are made readable, by their address
+	// if they have one. Structs and arrays are copied if they don't, so
+	// their fields and elements do.
+	v = accessible(v)
+	if (v.Kind() == reflect.Struct || v.Kind() == reflect.Array) && v.CanInterface() {
+		v = addressable(v)
+	}
+	if s, ok := f.text(v); ok {
+		return s
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Uintptr:
+		return fmt.Sprintf("%#x", v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits())
+	case reflect.Complex64, reflect.Complex128:
+		return fmt.Sprint(v.Complex())
+	case reflect.String:
+		return strconv.Quote(v.String())
+	case reflect.Interface:
+		if v.IsNil() {
+			return "nil"
+		}
+		return f.format(v.Elem(), true)
+	case reflect.Pointer:
+		if v.IsNil() {
+			return fmt.Sprintf("(%v)(nil)", v.Type())
+		}
+		vis, ok := f.enter(v)
+		if !ok {
+			return fmt.Sprintf("&<cycle %v>", v.Type().Elem())
+		}
+		defer delete(f.visiting, vis)
+		return "&" + f.format(v.Elem(), showType)
+	case reflect.Struct:
+		return f.formatStruct(v, showType)
+	case reflect.Slice:
+		if v.IsNil() {
+			return fmt.Sprintf("%v(nil)", v.Type())
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return formatBytes(v)
+		}
+		vis, ok := f.enter(v)
+		if !ok {
+			return fmt.Sprintf("<cycle %v>", v.Type())
+		}
+		defer delete(f.visiting, vis)
+		return f.formatList(v, showType)
+	case reflect.Array:
+		return f.formatList(v, showType)
+	case reflect.Map:
+		if v.IsNil() {
+			return fmt.Sprintf("%v(nil)", v.Type())
+		}
+		vis, ok := f.enter(v)
+		if !ok {
+			return fmt.Sprintf("<cycle %v>", v.Type())
+		}
+		defer delete(f.visiting, vis)
+		return f.formatMap(v, showType)
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		if v.IsNil() {
+			return fmt.Sprintf("(%v)(nil)", v.Type())
+		}
+		return fmt.Sprintf("(%v)(%#x)", v.Type(), v.Pointer())
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// text returns the text of errors, fmt.Stringer values and time.Time, if v
+// is one of them.
+func (f *formatter) text(v reflect.Value) (s string, ok bool) {
+	if !v.CanInterface() {
+		return "", false
+	}
+	if v.Type() == timeType {
+		return v.Interface().(time.Time).Format(time.RFC3339Nano), true
+	}
+	if !v.Type().Implements(errorType) && !v.Type().Implements(stringerType) {
+		return "", false
+	}
+	if v.Kind() == reflect.Pointer && v.IsNil() {
+		return "", false
+	}
+	defer func() {
+		if recover() != nil {
+			s, ok = "", false
+		}
+	}()
+	switch x := v.Interface().(type) {
+	case error:
+		return x.Error(), true
+	case fmt.Stringer:
+		return x.String(), true
+	}
+	return "", false
+}
+
+func (f *formatter) formatStruct(v reflect.Value, showType bool) string {
+	typ := v.Type()
+	fields := make([]string, 0, v.NumField())
+	for i := range v.NumField() {
+		fields = append(fields, typ.Field(i).Name+": "+f.format(v.Field(i), true))
+	}
+	return composite(typeName(typ, showType), fields)
+}
+
+func (f *formatter) formatList(v reflect.Value, showType bool) string {
+	n := v.Len()
+	elems := make([]string, 0, min(n, maxElems)+1)
+	for i := range min(n, maxElems) {
+		elems = append(elems, f.format(v.Index(i), false))
+	}
+	if n > maxElems {
+		elems = append(elems, fmt.Sprintf("... %d more", n-maxElems))
+	}
+	return composite(typeName(v.Type(), showType), elems)
+}
+
+func (f *formatter) formatMap(v reflect.Value, showType bool) string {
+	type entry struct {
+		key, elem string
+		k         reflect.Value
+	}
+	entries := make([]entry, 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		entries = append(entries, entry{f.format(iter.Key(), false), f.format(iter.Value(), false), iter.Key()})
+	}
+	slices.SortFunc(entries, func(a, b entry) int {
+		return compareKeys(a.k, b.k, a.key, b.key)
+	})
+
+	n := len(entries)
+	elems := make([]string, 0, min(n, maxElems)+1)
+	for _, e := range entries[:min(n, maxElems)] {
+		elems = append(elems, e.key+": "+e.elem)
+	}
+	if n > maxElems {
+		elems = append(elems, fmt.Sprintf("... %d more", n-maxElems))
+	}
+	return composite(typeName(v.Type(), showType), elems)
+}
+
+// compareKeys orders numbers and strings by value, and anything else by
+// its formatted text.
+func compareKeys(a, b reflect.Value, textA, textB string) int {
+	if a.Kind() == b.Kind() {
+		switch a.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return cmp.Compare(a.Int(), b.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return cmp.Compare(a.Uint(), b.Uint())
+		case reflect.Float32, reflect.Float64:
+			return cmp.Compare(a.Float(), b.Float())
+		case reflect.String:
+			return cmp.Compare(a.String(), b.String())
+		}
+	}
+	return cmp.Compare(textA, textB)
+}
+
+func formatBytes(v reflect.Value) string {
+	b := v.Bytes()
+	if len(b) <= maxElems && isPrintable(b) {
+		return fmt.Sprintf("%v(%q)", v.Type(), b)
+	}
+	dump := strings.TrimSuffix(hex.Dump(b[:min(len(b), maxBytes)]), "\n")
+	lines := strings.Split(dump, "\n")
+	if len(b) > maxBytes {
+		lines = append(lines, fmt.Sprintf("... %d more", len(b)-maxBytes))
+	}
+	return fmt.Sprintf("%v{\n\t%s\n}", v.Type(), strings.Join(lines, "\n\t"))
+}
+
+func isPrintable(b []byte) bool {
+	for _, c := range b {
+		if c < ' ' || c > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+func typeName(typ reflect.Type, show bool) string {
+	if !show {
+		return ""
+	}
+	return typ.String()
+}
+
+// composite lays out the elements on a single line when they fit, or one
+// per line, indented, otherwise.
+func composite(typ string, elems []string) string {
+	single := typ + "{" + strings.Join(elems, ", ") + "}"
+	if len(single) <= maxLineWidth && !strings.Contains(single, "\n") {
+		return single
+	}
+	var b strings.Builder
+	b.WriteString(typ + "{\n")
+	for _, e := range elems {
+		b.WriteString("\t" + strings.ReplaceAll(e, "\n", "\n\t") + ",\n")
+	}
+	b.WriteString("}")
+	return b.String()
+}
@@ -0,0 +1,110 @@
+package assert_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xandalm/go-testing/assert"
+)
+
+type node struct {
+	Value int
+	next  *node
+}
+
+// firstField holds a pointer to its first field, at its own address.
+type firstField struct {
+	First pointer
+	P     *pointer
+}
+
+type level int
+
+func (l level) String() string {
+	return fmt.Sprintf("level %d", int(l))
+}
+
+type event struct {
+	at   time.Time
+	kind level
+}
+
+type celsius float64
+
+func (c celsius) String() string {
+	return "25°C"
+}
+
+func TestFormat(t *testing.T) {
+	cyclic := &node{Value: 1}
+	cyclic.next = &node{Value: 2, next: cyclic}
+	cyclicSlice := []any{nil}
+	cyclicSlice[0] = cyclicSlice
+	cyclicMap := map[string]any{}
+	cyclicMap["self"] = cyclicMap
+	first := &firstField{First: pointer{1, 2}}
+	first.P = &first.First
+
+	cases := []struct {
+		name string
+		v    any
+		want string
+	}{
+		{"nil", nil, "nil"},
+		{"int", 42, "42"},
+		{"string", "foo\n", `"foo\n"`},
+		{"float", 0.5, "0.5"},
+		{"struct with unexported field", node{Value: 1}, "assert_test.node{Value: 1, next: (*assert_test.node)(nil)}"},
+		{"pointer", &pointer{1, 2}, "&assert_test.pointer{X: 1, Y: 2}"},
+		{"unexported time", event{at: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}, "assert_test.event{at: 2024-01-02T03:04:05Z, kind: level 0}"},
+		{"unexported Stringer", event{kind: level(2)}, "assert_test.event{at: 0001-01-01T00:00:00Z, kind: level 2}"},
+		{"unexported Stringer in map", map[string]event{"a": {kind: level(1)}}, `map[string]assert_test.event{"a": {at: 0001-01-01T00:00:00Z, kind: level 1}}`},
+		{"nil pointer", (*pointer)(nil), "(*assert_test.pointer)(nil)"},
+		{"cycle", cyclic, "&assert_test.node{\n\tValue: 1,\n\tnext: &assert_test.node{Value: 2, next: &<cycle assert_test.node>},\n}"},
+		{"cyclic slice", cyclicSlice, "[]interface {}{<cycle []interface {}>}"},
+		{"cyclic map", cyclicMap, `map[string]interface {}{"self": <cycle map[string]interface {}>}`},
+		{"pointer to first field", first, "&assert_test.firstField{\n\tFirst: assert_test.pointer{X: 1, Y: 2},\n\tP: &assert_test.pointer{X: 1, Y: 2},\n}"},
+		{"slice", []int{1, 2, 3}, "[]int{1, 2, 3}"},
+		{"nil slice", []int(nil), "[]int(nil)"},
+		{"sorted map", map[string]int{"b": 2, "c": 3, "a": 1}, `map[string]int{"a": 1, "b": 2, "c": 3}`},
+		{"numeric map keys", map[int]bool{10: true, 9: false}, "map[int]bool{9: false, 10: true}"},
+		{"printable bytes", []byte("hi"), `[]uint8("hi")`},
+		{"error", errors.New("boom"), "boom"},
+		{"stringer", celsius(25), "25°C"},
+		{"time", time.Date(2024, 5, 1, 10, 0, 0, 5, time.UTC), "2024-05-01T10:00:00.000000005Z"},
+		{"interface elements", []any{1, "a", pointer{}}, `[]interface {}{1, "a", assert_test.pointer{X: 0, Y: 0}}`},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := assert.Format(c.v); got != c.want {
+				t.Errorf("got %s, want %s", got, c.want)
+			}
+		})
+	}
+}
+
+func TestFormatLayout(t *testing.T) {
+	t.Run("long values are indented", func(t *testing.T) {
+		v := []pointer{{1, 2}, {3, 4}, {5, 6}, {7, 8}, {9, 10}}
+		want := "[]assert_test.pointer{\n\t{X: 1, Y: 2},\n\t{X: 3, Y: 4},\n\t{X: 5, Y: 6},\n\t{X: 7, Y: 8},\n\t{X: 9, Y: 10},\n}"
+		if got := assert.Format(v); got != want {
+			t.Errorf("got\n%s\nwant\n%s", got, want)
+		}
+	})
+	t.Run("long collections are cut", func(t *testing.T) {
+		got := assert.Format(make([]int, 40))
+		if !strings.HasSuffix(got, "\t... 8 more,\n}") {
+			t.Errorf("got %s", got)
+		}
+	})
+	t.Run("binary bytes are hex dumped", func(t *testing.T) {
+		got := assert.Format([]byte{0, 1, 'a', 0xff})
+		want := "[]uint8{\n\t00000000  00 01 61 ff                                       |..a.|\n}"
+		if got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	})
+}
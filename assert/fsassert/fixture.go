@@ -0,0 +1,158 @@
+package fsassert
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/xandalm/go-testing/assert"
+	"github.com/xandalm/go-testing/internal/txtar"
+)
+
+// UpdateEnv is the environment variable which, set to a non-empty value,
+// makes DirMatchesArchive write the archives instead of comparing them.
+const UpdateEnv = "FSASSERT_UPDATE"
+
+// Fixture writes the files of the txtar archive to a temporary directory
+// removed when the test ends, and returns the directory, to be used as the
+// working directory of a ServerLauncher for instance. Files named with a
+// trailing slash, and no content, are empty directories. The comment of
+// the archive is ignored.
+//
+//	dir := fsassert.Fixture(t, `
+//	-- go.mod --
+//	module example
+//	-- main.go --
+//	package main
+//	`)
+func Fixture(t testing.TB, archive string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for _, f := range txtar.Parse([]byte(archive)).Files {
+		p, isDir := archivePath(f)
+		if !fs.ValidPath(p) || p == "." {
+			t.Fatalf("fsassert: invalid file name %q in archive", f.Name)
+		}
+		name := filepath.Join(dir, filepath.FromSlash(p))
+		if isDir {
+			if err := os.MkdirAll(name, 0o755); err != nil {
+				t.Fatalf("fsassert: cannot write fixture, %v", err)
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			t.Fatalf("fsassert: cannot write fixture, %v", err)
+		}
+		if err := os.WriteFile(name, f.Data, 0o644); err != nil {
+			t.Fatalf("fsassert: cannot write fixture, %v", err)
+		}
+	}
+	return dir
+}
+
+// archivePath returns the path of the archive file f, and whether it's an
+// empty directory. Files named with a trailing slash and content aren't
+// directories, nor valid paths.
+func archivePath(f txtar.File) (p string, isDir bool) {
+	p, isDir = strings.CutSuffix(f.Name, "/")
+	if isDir && len(f.Data) > 0 {
+		return f.Name, false
+	}
+	return p, isDir
+}
+
+// FixtureFile is as Fixture, with the archive read from file, such as one
+// in testdata.
+func FixtureFile(t testing.TB, file string) string {
+	t.Helper()
+
+	b, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("fsassert: cannot read fixture, %v", err)
+	}
+	return Fixture(t, string(b))
+}
+
+// Snapshot returns the files of the directory tree rooted at dir as a
+// txtar archive, sorted by name. Empty directories are kept as files
+// named with a trailing slash, as Fixture reads them.
+func Snapshot(t testing.TB, dir string) string {
+	t.Helper()
+
+	a, err := snapshot(os.DirFS(dir))
+	if err != nil {
+		t.Fatalf("fsassert: cannot snapshot %s, %v", dir, err)
+	}
+	return string(txtar.Format(a))
+}
+
+func snapshot(fsys fs.FS) (*txtar.Archive, error) {
+	a := new(txtar.Archive)
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p == "." {
+				return nil
+			}
+			entries, err := fs.ReadDir(fsys, p)
+			if err == nil && len(entries) == 0 {
+				a.Files = append(a.Files, txtar.File{Name: p + "/"})
+			}
+			return err
+		}
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+		a.Files = append(a.Files, txtar.File{Name: p, Data: data})
+		return nil
+	})
+	return a, err
+}
+
+// DirMatchesArchive asserts the directory tree rooted at dir holds the
+// files of the txtar archive in file, a golden file usually in testdata,
+// as DirTreeEqual does. With UpdateEnv set, the archive is written with
+// the snapshot of dir instead, keeping its comment.
+func DirMatchesArchive(t testing.TB, dir, file string, out ...any) {
+	t.Helper()
+
+	b, err := os.ReadFile(file)
+	if err != nil && !(os.IsNotExist(err) && os.Getenv(UpdateEnv) != "") {
+		assert.Fail(t, assert.Failure{Message: fmt.Sprintf("cannot read archive: %v", err)}, out...)
+		return
+	}
+	want := txtar.Parse(b)
+
+	if os.Getenv(UpdateEnv) != "" {
+		got, err := snapshot(os.DirFS(dir))
+		if err != nil {
+			t.Fatalf("fsassert: cannot snapshot %s, %v", dir, err)
+		}
+		got.Comment = want.Comment
+		if err := os.WriteFile(file, txtar.Format(got), 0o644); err != nil {
+			t.Fatalf("fsassert: cannot update archive, %v", err)
+		}
+		t.Logf("updated %s", file)
+		return
+	}
+
+	wantFS := fstest.MapFS{}
+	for _, f := range want.Files {
+		p, isDir := archivePath(f)
+		if isDir {
+			wantFS[path.Clean(p)] = &fstest.MapFile{Mode: fs.ModeDir | 0o755}
+			continue
+		}
+		wantFS[path.Clean(p)] = &fstest.MapFile{Data: f.Data, Mode: 0o644}
+	}
+	DirTreeEqual(t, os.DirFS(dir), wantFS, out...)
+}
@@ -0,0 +1,81 @@
+package fsassert_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xandalm/go-testing/assert"
+	"github.com/xandalm/go-testing/assert/asserttest"
+	"github.com/xandalm/go-testing/assert/fsassert"
+)
+
+func TestFixture(t *testing.T) {
+	dir := fsassert.Fixture(t, `
+-- a.txt --
+alpha
+-- sub/dir/b.txt --
+beta
+`)
+	fsassert.FileContent(t, nil, filepath.Join(dir, "a.txt"), "alpha\n")
+	fsassert.FileContent(t, nil, filepath.Join(dir, "sub", "dir", "b.txt"), "beta\n")
+
+	assert.Equal(t, fsassert.Snapshot(t, dir), "-- a.txt --\nalpha\n-- sub/dir/b.txt --\nbeta\n")
+
+	t.Run("empty directories", func(t *testing.T) {
+		archive := "-- empty/ --\n-- sub/a.txt --\nalpha\n-- sub/empty/ --\n"
+		dir := fsassert.Fixture(t, archive)
+		fsassert.DirExists(t, nil, filepath.Join(dir, "sub", "empty"))
+		assert.Equal(t, fsassert.Snapshot(t, dir), archive)
+
+		golden := filepath.Join(t.TempDir(), "tree.txtar")
+		assert.Nil(t, os.WriteFile(golden, []byte(archive), 0o644))
+		asserttest.ExpectSuccess(t, func(t testing.TB) {
+			fsassert.DirMatchesArchive(t, dir, golden)
+		})
+		assert.Nil(t, os.Remove(filepath.Join(dir, "empty")))
+		got := fatal(t, func(t testing.TB) {
+			fsassert.DirMatchesArchive(t, dir, golden)
+		})
+		assert.Contains(t, got, "missing: empty/")
+	})
+
+	for _, name := range []string{"../escape.txt", "/abs.txt", ".", "./", "content/"} {
+		t.Run(name, func(t *testing.T) {
+			r := asserttest.ExpectFailure(t, func(t testing.TB) {
+				fsassert.Fixture(t, "-- "+name+" --\ncontent\n")
+			})
+			want := `fsassert: invalid file name "` + name + `" in archive`
+			if got := r.Messages("Fatal"); len(got) != 1 || got[0] != want {
+				t.Errorf("expected message %q, got %q", want, got)
+			}
+		})
+	}
+}
+
+func TestDirMatchesArchive(t *testing.T) {
+	dir := fsassert.FixtureFile(t, "testdata/site.txtar")
+	asserttest.ExpectSuccess(t, func(t testing.TB) {
+		fsassert.DirMatchesArchive(t, dir, "testdata/site.txtar")
+	})
+
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "index.html"), []byte("<h1>Welcome</h1>\n"), 0o644))
+	got := fatal(t, func(t testing.TB) {
+		fsassert.DirMatchesArchive(t, dir, "testdata/site.txtar")
+	})
+	assert.HasPrefix(t, got, "expected equal directory trees, but found 1 differences")
+	assert.Contains(t, got, "index.html: content differs")
+
+	t.Run("update", func(t *testing.T) {
+		golden := filepath.Join(t.TempDir(), "site.txtar")
+		assert.Nil(t, os.WriteFile(golden, []byte("The site.\n-- old.html --\n"), 0o644))
+		t.Setenv(fsassert.UpdateEnv, "1")
+
+		fsassert.DirMatchesArchive(t, dir, golden)
+		b, err := os.ReadFile(golden)
+		assert.Nil(t, err)
+		assert.True(t, strings.HasPrefix(string(b), "The site.\n-- blog/first.html --\n"), "unexpected archive %q", b)
+		assert.Contains(t, string(b), "-- index.html --\n<h1>Welcome</h1>\n")
+	})
+}
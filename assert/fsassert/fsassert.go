@@ -0,0 +1,205 @@
+// Package fsassert provides assertions on files and directories, and
+// fixture directories written from txtar archives.
+//
+// The assertions look names up in an fs.FS, such as an embed.FS or the
+// result of os.DirFS, or in the operating system file system when the
+// given fs.FS is nil, in which case names are OS paths.
+package fsassert
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/xandalm/go-testing/assert"
+	"github.com/xandalm/go-testing/internal/diff"
+)
+
+// osFS is the operating system file system, accepting any OS path as
+// name.
+type osFS struct{}
+
+func (osFS) Open(name string) (fs.File, error) {
+	return os.Open(name)
+}
+
+func (osFS) Stat(name string) (fs.FileInfo, error) {
+	return os.Stat(name)
+}
+
+func (osFS) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(name)
+}
+
+func (osFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	return os.ReadDir(name)
+}
+
+func orOS(fsys fs.FS) fs.FS {
+	if fsys == nil {
+		return osFS{}
+	}
+	return fsys
+}
+
+func FileExists(t testing.TB, fsys fs.FS, name string, out ...any) {
+	t.Helper()
+
+	info, err := fs.Stat(orOS(fsys), name)
+	switch {
+	case err != nil:
+		assert.Fail(t, assert.Failure{Message: fmt.Sprintf("expected file %q to exist, but %v", name, err)}, out...)
+	case info.IsDir():
+		assert.Fail(t, assert.Failure{Message: fmt.Sprintf("expected file %q to exist, but it's a directory", name)}, out...)
+	}
+}
+
+// NoFileExists asserts nothing, neither a file nor a directory, exists at
+// name.
+func NoFileExists(t testing.TB, fsys fs.FS, name string, out ...any) {
+	t.Helper()
+
+	info, err := fs.Stat(orOS(fsys), name)
+	if err == nil {
+		kind := "file"
+		if info.IsDir() {
+			kind = "directory"
+		}
+		assert.Fail(t, assert.Failure{Message: fmt.Sprintf("expected %q not to exist, but found a %s", name, kind)}, out...)
+	}
+}
+
+func DirExists(t testing.TB, fsys fs.FS, name string, out ...any) {
+	t.Helper()
+
+	info, err := fs.Stat(orOS(fsys), name)
+	switch {
+	case err != nil:
+		assert.Fail(t, assert.Failure{Message: fmt.Sprintf("expected directory %q to exist, but %v", name, err)}, out...)
+	case !info.IsDir():
+		assert.Fail(t, assert.Failure{Message: fmt.Sprintf("expected directory %q to exist, but it's a file", name)}, out...)
+	}
+}
+
+// readFile returns the content of the file, failing t if it can't be read.
+func readFile(t testing.TB, fsys fs.FS, name string, out []any) (string, bool) {
+	t.Helper()
+
+	b, err := fs.ReadFile(orOS(fsys), name)
+	if err != nil {
+		assert.Fail(t, assert.Failure{Message: fmt.Sprintf("cannot read file %q: %v", name, err)}, out...)
+		return "", false
+	}
+	return string(b), true
+}
+
+// FileContent asserts the content of the file is want, showing the
+// differing lines otherwise.
+func FileContent(t testing.TB, fsys fs.FS, name, want string, out ...any) {
+	t.Helper()
+
+	if got, ok := readFile(t, fsys, name, out); ok && got != want {
+		assert.Fail(t, assert.Failure{
+			Message: fmt.Sprintf("content of %q isn't the expected (-want +got)", name),
+			Diff:    diff.Content([]byte(got), []byte(want), "\n\t"),
+		}, out...)
+	}
+}
+
+func FileContains(t testing.TB, fsys fs.FS, name, sub string, out ...any) {
+	t.Helper()
+
+	if got, ok := readFile(t, fsys, name, out); ok && !strings.Contains(got, sub) {
+		assert.Fail(t, assert.Failure{
+			Message: fmt.Sprintf("expected content of %q to contain %q, but got %q", name, sub, got),
+			Actual:  fmt.Sprintf("%q", got),
+		}, out...)
+	}
+}
+
+// FileMatches asserts the content of the file matches the regular
+// expression pattern.
+func FileMatches(t testing.TB, fsys fs.FS, name, pattern string, out ...any) {
+	t.Helper()
+
+	re := regexp.MustCompile(pattern)
+	if got, ok := readFile(t, fsys, name, out); ok && !re.MatchString(got) {
+		assert.Fail(t, assert.Failure{
+			Message: fmt.Sprintf("expected content of %q to match `%s`, but got %q", name, pattern, got),
+			Actual:  fmt.Sprintf("%q", got),
+		}, out...)
+	}
+}
+
+// FileMode asserts the mode of the file is want. Only the permission bits
+// are compared when want has no type bits.
+func FileMode(t testing.TB, fsys fs.FS, name string, want fs.FileMode, out ...any) {
+	t.Helper()
+
+	info, err := fs.Stat(orOS(fsys), name)
+	if err != nil {
+		assert.Fail(t, assert.Failure{Message: fmt.Sprintf("cannot stat %q: %v", name, err)}, out...)
+		return
+	}
+	got := info.Mode()
+	if want.Type() == 0 {
+		got = got.Perm()
+	}
+	if got != want {
+		assert.Fail(t, assert.Failure{
+			Message:  fmt.Sprintf("expected mode of %q to be %v, but got %v", name, want, got),
+			Expected: want.String(),
+			Actual:   got.String(),
+		}, out...)
+	}
+}
+
+// DirContainsExactly asserts the entries of the directory, not recursing
+// into subdirectories, are the given names, in any order.
+func DirContainsExactly(t testing.TB, fsys fs.FS, dir string, names []string, out ...any) {
+	t.Helper()
+
+	entries, err := fs.ReadDir(orOS(fsys), dir)
+	if err != nil {
+		assert.Fail(t, assert.Failure{Message: fmt.Sprintf("cannot read directory %q: %v", dir, err)}, out...)
+		return
+	}
+	got := make([]string, len(entries))
+	for i, e := range entries {
+		got[i] = e.Name()
+	}
+	want := slices.Sorted(slices.Values(names))
+
+	var missing, unexpected []string
+	for _, name := range want {
+		if !slices.Contains(got, name) {
+			missing = append(missing, name)
+		}
+	}
+	for _, name := range got {
+		if !slices.Contains(want, name) {
+			unexpected = append(unexpected, name)
+		}
+	}
+	if len(missing) == 0 && len(unexpected) == 0 {
+		return
+	}
+
+	var diff strings.Builder
+	for _, name := range missing {
+		diff.WriteString("\n\tmissing: " + name)
+	}
+	for _, name := range unexpected {
+		diff.WriteString("\n\tunexpected: " + name)
+	}
+	assert.Fail(t, assert.Failure{
+		Message:  fmt.Sprintf("expected directory %q to contain exactly %q, but got %q", dir, want, got),
+		Expected: fmt.Sprintf("%q", want),
+		Actual:   fmt.Sprintf("%q", got),
+		Diff:     diff.String(),
+	}, out...)
+}
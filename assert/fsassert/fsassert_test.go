@@ -0,0 +1,183 @@
+package fsassert_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/xandalm/go-testing/assert/asserttest"
+	"github.com/xandalm/go-testing/assert/fsassert"
+)
+
+var fsys = fstest.MapFS{
+	"go.mod":          {Data: []byte("module example\n"), Mode: 0644},
+	"cmd/main.go":     {Data: []byte("package main\n\nfunc main() {}\n"), Mode: 0644},
+	"cmd/run.sh":      {Data: []byte("#!/bin/sh\n"), Mode: 0755},
+	"internal/doc.go": {Data: []byte("package internal\n")},
+}
+
+func fatal(t *testing.T, fn func(t testing.TB)) string {
+	t.Helper()
+	msgs := asserttest.ExpectFailure(t, fn).Messages("Fatal")
+	if len(msgs) != 1 {
+		t.Fatalf("expected a single fatal message, got %q", msgs)
+	}
+	return msgs[0]
+}
+
+func TestFS(t *testing.T) {
+	asserttest.ExpectSuccess(t, func(t testing.TB) {
+		fsassert.FileExists(t, fsys, "cmd/main.go")
+		fsassert.NoFileExists(t, fsys, "cmd/main_test.go")
+		fsassert.DirExists(t, fsys, "internal")
+		fsassert.FileContent(t, fsys, "go.mod", "module example\n")
+		fsassert.FileContains(t, fsys, "cmd/main.go", "func main")
+		fsassert.FileMatches(t, fsys, "cmd/main.go", `^package \w+`)
+		fsassert.FileMode(t, fsys, "cmd/run.sh", 0755)
+		fsassert.DirContainsExactly(t, fsys, "cmd", []string{"run.sh", "main.go"})
+	})
+
+	cases := []struct {
+		name string
+		fn   func(t testing.TB)
+		want string
+	}{
+		{"FileExists", func(t testing.TB) { fsassert.FileExists(t, fsys, "cmd") },
+			`expected file "cmd" to exist, but it's a directory`},
+		{"missing FileExists", func(t testing.TB) { fsassert.FileExists(t, fsys, "main.go") },
+			`expected file "main.go" to exist, but open main.go: file does not exist`},
+		{"NoFileExists", func(t testing.TB) { fsassert.NoFileExists(t, fsys, "internal") },
+			`expected "internal" not to exist, but found a directory`},
+		{"DirExists", func(t testing.TB) { fsassert.DirExists(t, fsys, "go.mod") },
+			`expected directory "go.mod" to exist, but it's a file`},
+		{"FileContains", func(t testing.TB) { fsassert.FileContains(t, fsys, "go.mod", "go 1.23") },
+			`expected content of "go.mod" to contain "go 1.23", but got "module example\n"`},
+		{"FileMatches", func(t testing.TB) { fsassert.FileMatches(t, fsys, "go.mod", `^go`) },
+			"expected content of \"go.mod\" to match `^go`, but got \"module example\\n\""},
+		{"unreadable FileContent", func(t testing.TB) { fsassert.FileContent(t, fsys, "go.sum", "") },
+			`cannot read file "go.sum": open go.sum: file does not exist`},
+		{"FileMode", func(t testing.TB) { fsassert.FileMode(t, fsys, "cmd/main.go", 0755) },
+			`expected mode of "cmd/main.go" to be -rwxr-xr-x, but got -rw-r--r--`},
+		{"DirContainsExactly", func(t testing.TB) { fsassert.DirContainsExactly(t, fsys, "cmd", []string{"main.go", "go.mod"}) },
+			"expected directory \"cmd\" to contain exactly [\"go.mod\" \"main.go\"], but got [\"main.go\" \"run.sh\"]\n" +
+				"differences:\n\tmissing: go.mod\n\tunexpected: run.sh"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := fatal(t, c.fn); got != c.want {
+				t.Errorf("got %q\nwant %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestFileContent(t *testing.T) {
+	got := fatal(t, func(t testing.TB) {
+		fsassert.FileContent(t, fsys, "cmd/main.go", "package main\n\nfunc main() {\n\tprintln()\n}\n")
+	})
+	want := `content of "cmd/main.go" isn't the expected (-want +got)
+differences:
+	  package main
+
+	- func main() {
+	- 	println()
+	- }
+	+ func main() {}`
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+
+	t.Run("long content", func(t *testing.T) {
+		lines := fstest.MapFS{"n.txt": {Data: []byte("1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12")}}
+		got := fatal(t, func(t testing.TB) {
+			fsassert.FileContent(t, lines, "n.txt", "1\n2\n3\n4\nfive\n6\n7\n8\n9\n10\n11\n12\n")
+		})
+		want := `content of "n.txt" isn't the expected (-want +got)
+differences:
+	...
+	  3
+	  4
+	- five
+	+ 5
+	  6
+	  7
+	...
+	  10
+	  11
+	- 12
+	+ 12\ no newline at end`
+		if got != want {
+			t.Errorf("got\n%s\nwant\n%s", got, want)
+		}
+	})
+}
+
+func TestOSPaths(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "out.txt"), []byte("generated\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	asserttest.ExpectSuccess(t, func(t testing.TB) {
+		fsassert.DirExists(t, nil, dir)
+		fsassert.FileExists(t, nil, filepath.Join(dir, "out.txt"))
+		fsassert.FileContent(t, nil, filepath.Join(dir, "out.txt"), "generated\n")
+		fsassert.FileMode(t, nil, filepath.Join(dir, "out.txt"), 0600)
+		fsassert.DirContainsExactly(t, nil, dir, []string{"out.txt"})
+		fsassert.DirTreeEqual(t, os.DirFS(dir), fstest.MapFS{"out.txt": {Data: []byte("generated\n")}})
+	})
+}
+
+func TestDirTreeEqual(t *testing.T) {
+	asserttest.ExpectSuccess(t, func(t testing.TB) {
+		fsassert.DirTreeEqual(t, fsys, fstest.MapFS{
+			"go.mod":          {Data: []byte("module example\n")},
+			"cmd/main.go":     {Data: []byte("package main\n\nfunc main() {}\n")},
+			"cmd/run.sh":      {Data: []byte("#!/bin/sh\n")},
+			"internal/doc.go": {Data: []byte("package internal\n")},
+		})
+	})
+
+	got := fatal(t, func(t testing.TB) {
+		fsassert.DirTreeEqual(t, fsys, fstest.MapFS{
+			"go.mod":       {Data: []byte("module example\n\ngo 1.23\n")},
+			"cmd/main.go":  {Data: []byte("package main\n\nfunc main() {}\n")},
+			"cmd/run.sh":   {Data: []byte{0, 1}},
+			"internal":     {Data: []byte("not a directory\n")},
+			"pkg/a/b.go":   {Data: []byte("package a\n")},
+			"pkg/a/c.go":   {Data: []byte("package a\n")},
+			"pkg.go":       {Data: []byte("package example\n")},
+			"vendor/x.txt": {},
+		})
+	})
+	want := `expected equal directory trees, but found 6 differences
+differences:
+	cmd/run.sh: content differs (-want +got)
+		binary content differs: 10 bytes, want 2 bytes
+	go.mod: content differs (-want +got)
+		  module example
+		-
+		- go 1.23
+	internal: got a directory, want a file
+	missing: pkg/
+	missing: pkg.go
+	missing: vendor/`
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+
+	t.Run("sub tree", func(t *testing.T) {
+		cmd, err := fsys.Sub("cmd")
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := fatal(t, func(t testing.TB) {
+			fsassert.DirTreeEqual(t, cmd, fstest.MapFS{"main.go": {Data: []byte("package main\n\nfunc main() {}\n")}})
+		})
+		if !strings.HasSuffix(got, "\n\tunexpected: run.sh") {
+			t.Errorf("unexpected message %q", got)
+		}
+	})
+}
@@ -0,0 +1,114 @@
+package fsassert
+
+import (
+	"fmt"
+	"io/fs"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/xandalm/go-testing/assert"
+	"github.com/xandalm/go-testing/internal/diff"
+)
+
+// DirTreeEqual asserts the trees rooted at got and want have the same
+// directories and files, with the same content, showing the differing lines
+// of each file otherwise. For OS directories, give os.DirFS(dir); for
+// subdirectories of an fs.FS, give fs.Sub(fsys, dir).
+func DirTreeEqual(t testing.TB, got, want fs.FS, out ...any) {
+	t.Helper()
+
+	if got == nil || want == nil {
+		panic("fsassert: nil file system")
+	}
+	gotTree, err := walk(got)
+	if err != nil {
+		assert.Fail(t, assert.Failure{Message: fmt.Sprintf("cannot walk the actual tree: %v", err)}, out...)
+		return
+	}
+	wantTree, err := walk(want)
+	if err != nil {
+		assert.Fail(t, assert.Failure{Message: fmt.Sprintf("cannot walk the expected tree: %v", err)}, out...)
+		return
+	}
+
+	var diffs strings.Builder
+	n := 0
+	union := maps.Clone(gotTree)
+	maps.Copy(union, wantTree)
+	// reported are the directories reported as a whole, whose content
+	// isn't reported again
+	reported := map[string]bool{}
+	for _, p := range slices.Sorted(maps.Keys(union)) {
+		if reported[path.Dir(p)] {
+			reported[p] = true
+			continue
+		}
+		g, inGot := gotTree[p]
+		w, inWant := wantTree[p]
+		switch {
+		case !inGot:
+			fmt.Fprintf(&diffs, "\n\tmissing: %s", display(p, w))
+		case !inWant:
+			fmt.Fprintf(&diffs, "\n\tunexpected: %s", display(p, g))
+		case g != w:
+			fmt.Fprintf(&diffs, "\n\t%s: got a %s, want a %s", p, kind(g), kind(w))
+		case !g:
+			gotContent, err := fs.ReadFile(got, p)
+			if err != nil {
+				fmt.Fprintf(&diffs, "\n\t%s: %v", p, err)
+				break
+			}
+			wantContent, err := fs.ReadFile(want, p)
+			if err != nil {
+				fmt.Fprintf(&diffs, "\n\t%s: %v", p, err)
+				break
+			}
+			if string(gotContent) == string(wantContent) {
+				continue
+			}
+			fmt.Fprintf(&diffs, "\n\t%s: content differs (-want +got)%s", p, diff.Content(gotContent, wantContent, "\n\t\t"))
+		default:
+			continue
+		}
+		n++
+		reported[p] = !inGot || !inWant || g != w
+	}
+	if n > 0 {
+		assert.Fail(t, assert.Failure{
+			Message: fmt.Sprintf("expected equal directory trees, but found %d differences", n),
+			Diff:    diffs.String(),
+		}, out...)
+	}
+}
+
+// walk returns the paths in fsys, telling whether each is a directory.
+func walk(fsys fs.FS) (map[string]bool, error) {
+	tree := map[string]bool{}
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p != "." {
+			tree[p] = d.IsDir()
+		}
+		return nil
+	})
+	return tree, err
+}
+
+func display(p string, dir bool) string {
+	if dir {
+		return p + "/"
+	}
+	return p
+}
+
+func kind(dir bool) string {
+	if dir {
+		return "directory"
+	}
+	return "file"
+}
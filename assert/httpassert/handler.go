@@ -0,0 +1,130 @@
+package httpassert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/xandalm/go-testing/assert"
+)
+
+// Request builds a request to exercise a handler in-process.
+type Request struct {
+	method  string
+	target  string
+	header  http.Header
+	query   url.Values
+	body    []byte
+	cookies []*http.Cookie
+}
+
+// NewRequest starts building a request for target, either a path or an
+// absolute URL, as httptest.NewRequest.
+func NewRequest(method, target string) *Request {
+	return &Request{
+		method: method,
+		target: target,
+		header: http.Header{},
+		query:  url.Values{},
+	}
+}
+
+// Header adds the header key with value.
+func (r *Request) Header(key, value string) *Request {
+	r.header.Add(key, value)
+	return r
+}
+
+// Query adds the query parameter key with value to those of the target.
+func (r *Request) Query(key, value string) *Request {
+	r.query.Add(key, value)
+	return r
+}
+
+func (r *Request) Body(body string) *Request {
+	r.body = []byte(body)
+	return r
+}
+
+// JSON sets the body to v encoded as JSON, and the content type
+// accordingly.
+func (r *Request) JSON(v any) *Request {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic("httpassert: cannot encode JSON body: " + err.Error())
+	}
+	r.body = b
+	r.header.Set("Content-Type", "application/json")
+	return r
+}
+
+func (r *Request) Cookie(c *http.Cookie) *Request {
+	r.cookies = append(r.cookies, c)
+	return r
+}
+
+// Build returns the built request.
+func (r *Request) Build() *http.Request {
+	req := httptest.NewRequest(r.method, r.target, bytes.NewReader(r.body))
+	if len(r.query) > 0 {
+		q := req.URL.Query()
+		for key, values := range r.query {
+			q[key] = append(q[key], values...)
+		}
+		req.URL.RawQuery = q.Encode()
+		req.RequestURI = req.URL.RequestURI()
+	}
+	for key, values := range r.header {
+		req.Header[key] = append(req.Header[key], values...)
+	}
+	for _, c := range r.cookies {
+		req.AddCookie(c)
+	}
+	return req
+}
+
+// Serve serves the built request with h, returning the recorded response.
+func (r *Request) Serve(h http.Handler) *httptest.ResponseRecorder {
+	if h == nil {
+		panic("httpassert: nil handler")
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, r.Build())
+	return rec
+}
+
+// Body asserts the body of the response is matched by m.
+func Body[R Response](t testing.TB, r R, m Matcher, out ...any) {
+	t.Helper()
+
+	if m == nil {
+		panic("httpassert: nil matcher")
+	}
+	if got := readBody(t, result(r)); !m.Match(got) {
+		assert.Fail(t, assert.Failure{
+			Message:  fmt.Sprintf("expected body %v, but got %q", m, got),
+			Expected: m.String(),
+			Actual:   fmt.Sprintf("%q", got),
+		}, out...)
+	}
+}
+
+// HandlerReturns serves a request made of method, target and body (none if
+// empty) with h, and asserts the response has the status wantStatus and a
+// body matched by wantBody. It returns the recorded response for further
+// assertions.
+func HandlerReturns(t testing.TB, h http.Handler, method, target, body string, wantStatus int, wantBody Matcher, out ...any) *httptest.ResponseRecorder {
+	t.Helper()
+
+	if wantBody == nil {
+		panic("httpassert: nil matcher")
+	}
+	rec := NewRequest(method, target).Body(body).Serve(h)
+	StatusCode(t, rec, wantStatus, out...)
+	Body(t, rec, wantBody, out...)
+	return rec
+}
@@ -0,0 +1,63 @@
+package httpassert_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/xandalm/go-testing/assert/asserttest"
+	"github.com/xandalm/go-testing/assert/httpassert"
+)
+
+var echo = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	var body map[string]any
+	if r.Header.Get("Content-Type") == "application/json" {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	c, _ := r.Cookie("session")
+	fmt.Fprintf(w, "%s %s q=%v x=%q session=%v body=%v", r.Method, r.URL.Path, r.URL.Query(), r.Header.Get("X-Id"), c, body)
+})
+
+func TestRequest(t *testing.T) {
+	rec := httpassert.NewRequest("POST", "/items?a=1").
+		Query("b", "2").
+		Header("X-Id", "42").
+		Cookie(&http.Cookie{Name: "session", Value: "abc"}).
+		JSON(map[string]int{"n": 1}).
+		Serve(echo)
+
+	asserttest.ExpectSuccess(t, func(t testing.TB) {
+		httpassert.StatusCode(t, rec, http.StatusOK)
+		httpassert.BodyEqual(t, rec, `POST /items q=map[a:[1] b:[2]] x="42" session=session=abc body=map[n:1]`)
+	})
+
+	req := httpassert.NewRequest("GET", "/items").Query("a", "1").Build()
+	if req.RequestURI != "/items?a=1" {
+		t.Errorf("request URI should include the query, got %q", req.RequestURI)
+	}
+}
+
+func TestHandlerReturns(t *testing.T) {
+	asserttest.ExpectSuccess(t, func(t testing.TB) {
+		rec := httpassert.HandlerReturns(t, echo, "GET", "/", "", http.StatusOK, httpassert.Contains("GET /"))
+		httpassert.HeaderContains(t, rec, "Content-Type", "text/plain")
+	})
+
+	got := fatal(t, func(t testing.TB) {
+		httpassert.HandlerReturns(t, echo, "GET", "/", "", http.StatusOK, httpassert.Matches(`^POST`))
+	})
+	if want := "expected body to match `^POST`, but got " + `"GET / q=map[] x=\"\" session= body=map[]"`; got != want {
+		t.Errorf("got %q\nwant %q", got, want)
+	}
+
+	got = fatal(t, func(t testing.TB) {
+		httpassert.HandlerReturns(t, http.NotFoundHandler(), "GET", "/", "", http.StatusOK, httpassert.Any())
+	})
+	if want := "expected status 200 OK, but got 404 Not Found"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
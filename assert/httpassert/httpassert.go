@@ -0,0 +1,282 @@
+// Package httpassert provides assertions on HTTP responses, either received
+// from a server or recorded from a handler.
+package httpassert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/xandalm/go-testing/assert"
+)
+
+// Response is the type of the responses the assertions inspect.
+type Response interface {
+	*http.Response | *httptest.ResponseRecorder
+}
+
+func result[R Response](r R) *http.Response {
+	var res *http.Response
+	switch r := any(r).(type) {
+	case *http.Response:
+		res = r
+	case *httptest.ResponseRecorder:
+		if r != nil {
+			res = r.Result()
+		}
+	}
+	if res == nil {
+		panic("httpassert: nil response")
+	}
+	return res
+}
+
+// readBody reads the whole body of res, replacing it with a reader of the
+// same content so that it can be read again.
+func readBody(t testing.TB, res *http.Response) string {
+	t.Helper()
+
+	if res.Body == nil || res.Body == http.NoBody {
+		return ""
+	}
+	b, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	res.Body = io.NopCloser(bytes.NewReader(b))
+	if err != nil {
+		assert.Fail(t, assert.Failure{Message: fmt.Sprintf("failed reading response body: %v", err)})
+	}
+	return string(b)
+}
+
+func status(code int) string {
+	return fmt.Sprintf("%d %s", code, http.StatusText(code))
+}
+
+func StatusCode[R Response](t testing.TB, r R, want int, out ...any) {
+	t.Helper()
+
+	res := result(r)
+	if res.StatusCode != want {
+		assert.Fail(t, assert.Failure{
+			Message:  fmt.Sprintf("expected status %s, but got %s", status(want), status(res.StatusCode)),
+			Expected: status(want),
+			Actual:   status(res.StatusCode),
+		}, out...)
+	}
+}
+
+// StatusIn asserts the status code is in the range [lo, hi], e.g. 200 to 299
+// for any success.
+func StatusIn[R Response](t testing.TB, r R, lo, hi int, out ...any) {
+	t.Helper()
+
+	if lo > hi {
+		panic("httpassert: invalid status range")
+	}
+	res := result(r)
+	if res.StatusCode < lo || res.StatusCode > hi {
+		assert.Fail(t, assert.Failure{
+			Message:  fmt.Sprintf("expected status in [%d, %d], but got %s", lo, hi, status(res.StatusCode)),
+			Expected: fmt.Sprintf("[%d, %d]", lo, hi),
+			Actual:   status(res.StatusCode),
+		}, out...)
+	}
+}
+
+// Header asserts one of the values of the header key is want.
+func Header[R Response](t testing.TB, r R, key, want string, out ...any) {
+	t.Helper()
+
+	values := result(r).Header.Values(key)
+	if !slices.Contains(values, want) {
+		assert.Fail(t, assert.Failure{
+			Message:  fmt.Sprintf("expected header %q to be %q, but got %s", key, want, headerValues(values)),
+			Expected: fmt.Sprintf("%q", want),
+			Actual:   headerValues(values),
+		}, out...)
+	}
+}
+
+// HeaderContains asserts one of the values of the header key contains sub.
+func HeaderContains[R Response](t testing.TB, r R, key, sub string, out ...any) {
+	t.Helper()
+
+	values := result(r).Header.Values(key)
+	if !slices.ContainsFunc(values, func(v string) bool { return strings.Contains(v, sub) }) {
+		assert.Fail(t, assert.Failure{
+			Message: fmt.Sprintf("expected header %q to contain %q, but got %s", key, sub, headerValues(values)),
+			Actual:  headerValues(values),
+		}, out...)
+	}
+}
+
+func headerValues(values []string) string {
+	switch len(values) {
+	case 0:
+		return "no value"
+	case 1:
+		return fmt.Sprintf("%q", values[0])
+	default:
+		return fmt.Sprintf("%q", values)
+	}
+}
+
+// ContentType asserts the media type of the response is want. Parameters,
+// such as the charset, are only compared when want has them.
+func ContentType[R Response](t testing.TB, r R, want string, out ...any) {
+	t.Helper()
+
+	wantType, wantParams, err := mime.ParseMediaType(want)
+	if err != nil {
+		panic("httpassert: invalid content type: " + err.Error())
+	}
+	got := result(r).Header.Get("Content-Type")
+	gotType, gotParams, err := mime.ParseMediaType(got)
+	if err == nil && gotType == wantType && (len(wantParams) == 0 || maps.Equal(gotParams, wantParams)) {
+		return
+	}
+	assert.Fail(t, assert.Failure{
+		Message:  fmt.Sprintf("expected content type %q, but got %q", want, got),
+		Expected: fmt.Sprintf("%q", want),
+		Actual:   fmt.Sprintf("%q", got),
+	}, out...)
+}
+
+func BodyEqual[R Response](t testing.TB, r R, want string, out ...any) {
+	t.Helper()
+
+	if got := readBody(t, result(r)); got != want {
+		assert.Fail(t, assert.Failure{
+			Message:  fmt.Sprintf("expected body %q, but got %q", want, got),
+			Expected: fmt.Sprintf("%q", want),
+			Actual:   fmt.Sprintf("%q", got),
+		}, out...)
+	}
+}
+
+func BodyContains[R Response](t testing.TB, r R, sub string, out ...any) {
+	t.Helper()
+
+	if got := readBody(t, result(r)); !strings.Contains(got, sub) {
+		assert.Fail(t, assert.Failure{
+			Message: fmt.Sprintf("expected body to contain %q, but got %q", sub, got),
+			Actual:  fmt.Sprintf("%q", got),
+		}, out...)
+	}
+}
+
+// BodyJSONEq asserts the body and want are equivalent JSON documents,
+// regardless of spacing and of the order of object keys.
+func BodyJSONEq[R Response](t testing.TB, r R, want string, out ...any) {
+	t.Helper()
+
+	var wantDoc any
+	if err := json.Unmarshal([]byte(want), &wantDoc); err != nil {
+		panic("httpassert: invalid expected JSON: " + err.Error())
+	}
+	body := readBody(t, result(r))
+	var gotDoc any
+	if err := json.Unmarshal([]byte(body), &gotDoc); err != nil {
+		assert.Fail(t, assert.Failure{
+			Message: fmt.Sprintf("expected JSON body, but got %q: %v", body, err),
+			Actual:  fmt.Sprintf("%q", body),
+		}, out...)
+		return
+	}
+	if len(out) == 0 {
+		out = []any{"expected JSON body %s, but got %s", want, body}
+	}
+	assert.Equal(t, gotDoc, wantDoc, out...)
+}
+
+// Redirects asserts the response is a redirection to the given location:
+// its status is 301, 302, 303, 307 or 308 and its Location header is to.
+// Relative locations are resolved against the request URL, if known.
+func Redirects[R Response](t testing.TB, r R, to string, out ...any) {
+	t.Helper()
+
+	res := result(r)
+	if !isRedirect(res.StatusCode) {
+		assert.Fail(t, assert.Failure{
+			Message:  fmt.Sprintf("expected redirect to %q, but got status %s", to, status(res.StatusCode)),
+			Expected: fmt.Sprintf("%q", to),
+			Actual:   status(res.StatusCode),
+		}, out...)
+		return
+	}
+	loc := res.Header.Get("Location")
+	if loc == "" {
+		assert.Fail(t, assert.Failure{
+			Message:  fmt.Sprintf("expected redirect to %q, but got no Location header", to),
+			Expected: fmt.Sprintf("%q", to),
+		}, out...)
+		return
+	}
+	if loc == to || sameLocation(res, loc, to) {
+		return
+	}
+	assert.Fail(t, assert.Failure{
+		Message:  fmt.Sprintf("expected redirect to %q, but got %q", to, loc),
+		Expected: fmt.Sprintf("%q", to),
+		Actual:   fmt.Sprintf("%q", loc),
+	}, out...)
+}
+
+func isRedirect(code int) bool {
+	switch code {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	}
+	return false
+}
+
+func sameLocation(res *http.Response, loc, to string) bool {
+	if res.Request == nil || res.Request.URL == nil || loc == "" {
+		return false
+	}
+	a, err := res.Request.URL.Parse(loc)
+	if err != nil {
+		return false
+	}
+	b, err := res.Request.URL.Parse(to)
+	return err == nil && a.String() == b.String()
+}
+
+// Cookie asserts the response sets the cookie name with a value matched by
+// m.
+func Cookie[R Response](t testing.TB, r R, name string, m Matcher, out ...any) {
+	t.Helper()
+
+	if m == nil {
+		panic("httpassert: nil matcher")
+	}
+	cookies := result(r).Cookies()
+	i := slices.IndexFunc(cookies, func(c *http.Cookie) bool { return c.Name == name })
+	if i < 0 {
+		names := make([]string, len(cookies))
+		for i, c := range cookies {
+			names[i] = c.Name
+		}
+		assert.Fail(t, assert.Failure{
+			Message: fmt.Sprintf("expected cookie %q, but response set %q", name, names),
+			Actual:  fmt.Sprintf("%q", names),
+		}, out...)
+		return
+	}
+	if v := cookies[i].Value; !m.Match(v) {
+		assert.Fail(t, assert.Failure{
+			Message:  fmt.Sprintf("expected cookie %q %v, but got %q", name, m, v),
+			Expected: m.String(),
+			Actual:   fmt.Sprintf("%q", v),
+		}, out...)
+	}
+}
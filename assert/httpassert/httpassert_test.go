@@ -0,0 +1,241 @@
+package httpassert_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/xandalm/go-testing/assert"
+	"github.com/xandalm/go-testing/assert/asserttest"
+	"github.com/xandalm/go-testing/assert/httpassert"
+)
+
+var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/users":
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Header().Add("Cache-Control", "no-store")
+		w.Header().Add("Cache-Control", "private")
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc123"})
+		io.WriteString(w, `{"users": [{"name": "alice", "admin": true}]}`)
+	case "/old":
+		http.Redirect(w, r, "/users", http.StatusMovedPermanently)
+	default:
+		http.NotFound(w, r)
+	}
+})
+
+func record(method, target string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+	return rec
+}
+
+func fatal(t *testing.T, fn func(t testing.TB)) string {
+	t.Helper()
+	msgs := asserttest.ExpectFailure(t, fn).Messages("Fatal")
+	if len(msgs) != 1 {
+		t.Fatalf("expected a single fatal message, got %q", msgs)
+	}
+	return msgs[0]
+}
+
+func TestRecorder(t *testing.T) {
+	rec := record("GET", "/users")
+
+	asserttest.ExpectSuccess(t, func(t testing.TB) {
+		httpassert.StatusCode(t, rec, http.StatusOK)
+		httpassert.StatusIn(t, rec, 200, 299)
+		httpassert.Header(t, rec, "Cache-Control", "private")
+		httpassert.HeaderContains(t, rec, "Content-Type", "json")
+		httpassert.ContentType(t, rec, "application/json")
+		httpassert.ContentType(t, rec, "application/json; charset=utf-8")
+		httpassert.BodyContains(t, rec, "alice")
+		httpassert.BodyJSONEq(t, rec, `{"users":[{"admin":true,"name":"alice"}]}`)
+		httpassert.Cookie(t, rec, "session", httpassert.Matches(`^[a-z]+\d+$`))
+	})
+
+	t.Run("failures", func(t *testing.T) {
+		cases := []struct {
+			name string
+			fn   func(t testing.TB)
+			want string
+		}{
+			{"StatusCode", func(t testing.TB) { httpassert.StatusCode(t, rec, http.StatusCreated) },
+				"expected status 201 Created, but got 200 OK"},
+			{"StatusIn", func(t testing.TB) { httpassert.StatusIn(t, rec, 400, 499) },
+				"expected status in [400, 499], but got 200 OK"},
+			{"Header", func(t testing.TB) { httpassert.Header(t, rec, "Cache-Control", "no-cache") },
+				`expected header "Cache-Control" to be "no-cache", but got ["no-store" "private"]`},
+			{"missing Header", func(t testing.TB) { httpassert.Header(t, rec, "ETag", "x") },
+				`expected header "ETag" to be "x", but got no value`},
+			{"HeaderContains", func(t testing.TB) { httpassert.HeaderContains(t, rec, "Content-Type", "xml") },
+				`expected header "Content-Type" to contain "xml", but got "application/json; charset=utf-8"`},
+			{"ContentType", func(t testing.TB) { httpassert.ContentType(t, rec, "text/plain") },
+				`expected content type "text/plain", but got "application/json; charset=utf-8"`},
+			{"ContentType params", func(t testing.TB) { httpassert.ContentType(t, rec, "application/json; charset=latin1") },
+				`expected content type "application/json; charset=latin1", but got "application/json; charset=utf-8"`},
+			{"BodyContains", func(t testing.TB) { httpassert.BodyContains(t, rec, "bob") },
+				`expected body to contain "bob", but got "{\"users\": [{\"name\": \"alice\", \"admin\": true}]}"`},
+			{"Cookie", func(t testing.TB) { httpassert.Cookie(t, rec, "session", httpassert.Equals("xyz")) },
+				`expected cookie "session" to equal "xyz", but got "abc123"`},
+			{"missing Cookie", func(t testing.TB) { httpassert.Cookie(t, rec, "token", httpassert.Any()) },
+				`expected cookie "token", but response set ["session"]`},
+		}
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				if got := fatal(t, c.fn); got != c.want {
+					t.Errorf("got %q\nwant %q", got, c.want)
+				}
+			})
+		}
+	})
+
+	t.Run("BodyJSONEq failure", func(t *testing.T) {
+		got := fatal(t, func(t testing.TB) {
+			httpassert.BodyJSONEq(t, rec, `{"users":[{"admin":false,"name":"alice"}]}`)
+		})
+		for _, want := range []string{
+			`expected JSON body {"users":[{"admin":false,"name":"alice"}]}, but got`,
+			`["users"][0]["admin"]: true != false`,
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("message should contain %q, got %q", want, got)
+			}
+		}
+	})
+}
+
+func TestResponse(t *testing.T) {
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+
+	res, err := srv.Client().Get(srv.URL + "/users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	asserttest.ExpectSuccess(t, func(t testing.TB) {
+		httpassert.StatusCode(t, res, http.StatusOK)
+		httpassert.BodyContains(t, res, "alice")
+		httpassert.BodyJSONEq(t, res, `{"users":[{"name":"alice","admin":true}]}`)
+	})
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(body), "alice") {
+		t.Errorf("body should be restored after the assertions, got %q", body)
+	}
+
+	t.Run("not JSON", func(t *testing.T) {
+		res, err := srv.Client().Get(srv.URL + "/missing")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+
+		got := fatal(t, func(t testing.TB) {
+			httpassert.BodyJSONEq(t, res, `{}`)
+		})
+		if !strings.HasPrefix(got, `expected JSON body, but got "404 page not found\n"`) {
+			t.Errorf("unexpected message %q", got)
+		}
+	})
+}
+
+func TestRedirects(t *testing.T) {
+	rec := record("GET", "/old")
+
+	asserttest.ExpectSuccess(t, func(t testing.TB) {
+		httpassert.Redirects(t, rec, "/users")
+	})
+	got := fatal(t, func(t testing.TB) {
+		httpassert.Redirects(t, rec, "/new")
+	})
+	if want := `expected redirect to "/new", but got "/users"`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	got = fatal(t, func(t testing.TB) {
+		httpassert.Redirects(t, record("GET", "/users"), "/new")
+	})
+	if want := `expected redirect to "/new", but got status 200 OK`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	for _, code := range []int{http.StatusMultipleChoices, http.StatusNotModified} {
+		rec := httptest.NewRecorder()
+		rec.Header().Set("Location", "/users")
+		rec.WriteHeader(code)
+		got = fatal(t, func(t testing.TB) {
+			httpassert.Redirects(t, rec, "/users")
+		})
+		if want := fmt.Sprintf("expected redirect to \"/users\", but got status %d %s", code, http.StatusText(code)); got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	}
+	noLocation := httptest.NewRecorder()
+	noLocation.WriteHeader(http.StatusFound)
+	got = fatal(t, func(t testing.TB) {
+		httpassert.Redirects(t, noLocation, "/users")
+	})
+	if want := `expected redirect to "/users", but got no Location header`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	t.Run("relative location", func(t *testing.T) {
+		srv := httptest.NewServer(handler)
+		defer srv.Close()
+
+		cli := srv.Client()
+		cli.CheckRedirect = func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+		res, err := cli.Get(srv.URL + "/old")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+
+		asserttest.ExpectSuccess(t, func(t testing.TB) {
+			httpassert.Redirects(t, res, srv.URL+"/users")
+		})
+	})
+}
+
+func TestFailureLocation(t *testing.T) {
+	var buf bytes.Buffer
+	assert.SetReporter(assert.JSONReporter{W: &buf})
+	t.Cleanup(func() {
+		assert.SetReporter(nil)
+	})
+
+	fatal(t, func(t testing.TB) {
+		httpassert.BodyJSONEq(t, record("GET", "/users"), `[]`)
+	})
+	var f assert.Failure
+	if err := json.Unmarshal(buf.Bytes(), &f); err != nil {
+		t.Fatal(err)
+	}
+	if f.Assertion != "BodyJSONEq" || f.File != "httpassert_test.go" {
+		t.Errorf("failure should be located at the call to BodyJSONEq, got %+v", f)
+	}
+}
+
+func TestNilResponse(t *testing.T) {
+	asserttest.ExpectSuccess(t, func(t testing.TB) {
+		defer func() {
+			if r := recover(); r != "httpassert: nil response" {
+				t.Errorf("unexpected panic %v", r)
+			}
+		}()
+		var res *http.Response
+		httpassert.StatusCode(t, res, http.StatusOK)
+	})
+}
@@ -0,0 +1,55 @@
+package httpassert
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Matcher matches the strings found in responses, such as cookie values.
+// Its String describes what it matches, completing "expected ... " in
+// failure messages.
+type Matcher interface {
+	Match(s string) bool
+	String() string
+}
+
+type matcher struct {
+	desc  string
+	match func(s string) bool
+}
+
+func (m matcher) Match(s string) bool {
+	return m.match(s)
+}
+
+func (m matcher) String() string {
+	return m.desc
+}
+
+// Equals matches want.
+func Equals(want string) Matcher {
+	return matcher{fmt.Sprintf("to equal %q", want), func(s string) bool {
+		return s == want
+	}}
+}
+
+// Contains matches the strings containing sub.
+func Contains(sub string) Matcher {
+	return matcher{fmt.Sprintf("to contain %q", sub), func(s string) bool {
+		return strings.Contains(s, sub)
+	}}
+}
+
+// Matches matches the strings matching the regular expression pattern.
+func Matches(pattern string) Matcher {
+	re := regexp.MustCompile(pattern)
+	return matcher{fmt.Sprintf("to match `%s`", pattern), re.MatchString}
+}
+
+// Any matches any string.
+func Any() Matcher {
+	return matcher{"to have any value", func(string) bool {
+		return true
+	}}
+}
@@ -0,0 +1,104 @@
+package logtest
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/xandalm/go-testing/assert"
+)
+
+func (h *Handler) bound() {
+	if h.t == nil {
+		panic("logtest: handler isn't bound to a test")
+	}
+}
+
+// LogContains asserts a record was logged at level with message msg and
+// the given attributes, among others. The attributes are given as to
+// slog.Logger.Log: key/value pairs or slog.Attr values, including groups.
+// Their values are compared as by assert.Equal.
+func (h *Handler) LogContains(level slog.Level, msg string, attrs ...any) {
+	h.bound()
+	h.t.Helper()
+
+	want := attrMap(attrs)
+	records := h.Records()
+	if slices.ContainsFunc(records, func(r Record) bool {
+		return r.Level == level && r.Message == msg && hasAttrs(r, want)
+	}) {
+		return
+	}
+	expected := Record{Level: level, Message: msg, Attrs: want}.String()
+	assert.Fail(h.t, assert.Failure{
+		Message:  fmt.Sprintf("expected a record %s, but got none among %d records", expected, len(records)),
+		Expected: expected,
+	})
+}
+
+// NoLogsAbove asserts no record was logged at a level above level.
+func (h *Handler) NoLogsAbove(level slog.Level) {
+	h.bound()
+	h.t.Helper()
+
+	var above []string
+	for _, r := range h.Records() {
+		if r.Level > level {
+			above = append(above, r.String())
+		}
+	}
+	if len(above) > 0 {
+		assert.Fail(h.t, assert.Failure{
+			Message: fmt.Sprintf("expected no records above %v, but got:\n\t%s", level, strings.Join(above, "\n\t")),
+		})
+	}
+}
+
+// LogCount asserts want records were logged at level with message msg.
+// An empty msg counts the records of any message.
+func (h *Handler) LogCount(level slog.Level, msg string, want int) {
+	h.bound()
+	h.t.Helper()
+
+	n := 0
+	for _, r := range h.Records() {
+		if r.Level == level && (msg == "" || r.Message == msg) {
+			n++
+		}
+	}
+	if n != want {
+		desc := fmt.Sprintf("%v records", level)
+		if msg != "" {
+			desc = fmt.Sprintf("%v records %q", level, msg)
+		}
+		assert.Fail(h.t, assert.Failure{
+			Message:  fmt.Sprintf("expected %d %s, but got %d", want, desc, n),
+			Expected: fmt.Sprint(want),
+			Actual:   fmt.Sprint(n),
+		})
+	}
+}
+
+// attrMap flattens the attributes given as to slog.Logger.Log.
+func attrMap(attrs []any) map[string]any {
+	r := slog.NewRecord(time.Time{}, 0, "", 0)
+	r.Add(attrs...)
+	m := map[string]any{}
+	r.Attrs(func(a slog.Attr) bool {
+		flatten(m, "", a)
+		return true
+	})
+	return m
+}
+
+func hasAttrs(r Record, want map[string]any) bool {
+	for key, v := range want {
+		got, ok := r.Attrs[key]
+		if !ok || !assert.Equivalent(got, v) {
+			return false
+		}
+	}
+	return true
+}
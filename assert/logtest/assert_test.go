@@ -0,0 +1,91 @@
+package logtest_test
+
+import (
+	"log"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/xandalm/go-testing/assert"
+	"github.com/xandalm/go-testing/assert/asserttest"
+	"github.com/xandalm/go-testing/assert/logtest"
+)
+
+func audit(logger *slog.Logger, actor string) {
+	logger.Info("user deleted", slog.Group("audit", "actor", actor, "target", 42))
+	logger.Warn("cache stale")
+}
+
+func TestAssertions(t *testing.T) {
+	asserttest.ExpectSuccess(t, func(t testing.TB) {
+		h := logtest.New(t)
+		audit(slog.New(h), "alice")
+
+		h.LogContains(slog.LevelInfo, "user deleted", "audit.actor", "alice")
+		h.LogContains(slog.LevelInfo, "user deleted", slog.Group("audit", "target", 42))
+		h.LogContains(slog.LevelWarn, "cache stale")
+		h.NoLogsAbove(slog.LevelWarn)
+		h.LogCount(slog.LevelInfo, "user deleted", 1)
+		h.LogCount(slog.LevelWarn, "", 1)
+
+		// values are compared as by assert.Equal
+		now := time.Now()
+		slog.New(h).Info("expired", "at", now)
+		h.LogContains(slog.LevelInfo, "expired", "at", now.UTC())
+	})
+
+	cases := []struct {
+		name string
+		fn   func(h *logtest.Handler)
+		want string
+	}{
+		{"LogContains", func(h *logtest.Handler) { h.LogContains(slog.LevelInfo, "user deleted", "audit.actor", "bob") },
+			`expected a record INFO "user deleted" audit.actor=bob, but got none among 2 records`},
+		{"NoLogsAbove", func(h *logtest.Handler) { h.NoLogsAbove(slog.LevelInfo) },
+			"expected no records above INFO, but got:\n\tWARN \"cache stale\""},
+		{"LogCount", func(h *logtest.Handler) { h.LogCount(slog.LevelInfo, "user deleted", 2) },
+			`expected 2 INFO records "user deleted", but got 1`},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := asserttest.ExpectFailure(t, func(t testing.TB) {
+				h := logtest.New(t)
+				audit(slog.New(h), "alice")
+				c.fn(h)
+			})
+			if got := r.Messages("Fatal"); len(got) != 1 || got[0] != c.want {
+				t.Errorf("got %q, want %q", got, c.want)
+			}
+			logs := r.Messages("Log")
+			want := "logged records:\n\tINFO \"user deleted\" audit.actor=alice audit.target=42\n\tWARN \"cache stale\""
+			if len(logs) != 1 || logs[0] != want {
+				t.Errorf("records should be logged on failure, got %q", logs)
+			}
+		})
+	}
+
+	t.Run("unbound handler", func(t *testing.T) {
+		assert.PanicIs(t, func() {
+			logtest.NewHandler().NoLogsAbove(slog.LevelInfo)
+		}, "logtest: handler isn't bound to a test")
+	})
+}
+
+func TestAttach(t *testing.T) {
+	orig := slog.Default()
+
+	t.Run("attached", func(t *testing.T) {
+		h := logtest.Attach(t)
+		slog.Info("from slog")
+		log.Print("from log")
+		audit(slog.Default().With("req", 1), "bob")
+
+		h.LogCount(slog.LevelInfo, "", 3)
+		h.LogContains(slog.LevelInfo, "from log")
+		h.LogContains(slog.LevelInfo, "user deleted", "req", 1, "audit.actor", "bob")
+	})
+
+	if slog.Default() != orig {
+		t.Error("default logger should be restored on cleanup")
+	}
+}
@@ -0,0 +1,182 @@
+// Package logtest provides a log/slog handler recording the logged records
+// for tests to inspect and assert on.
+package logtest
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Record is a logged record with its attributes flattened: the key of an
+// attribute in a group is the group name and the attribute key joined by a
+// dot, e.g. "req.method".
+type Record struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string
+	Attrs   map[string]any
+}
+
+func (r Record) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%v %q", r.Level, r.Message)
+	for _, key := range slices.Sorted(maps.Keys(r.Attrs)) {
+		fmt.Fprintf(&b, " %s=%v", key, r.Attrs[key])
+	}
+	return b.String()
+}
+
+// Handler is a slog.Handler recording every record, whatever its level.
+// Handlers derived by WithAttrs and WithGroup record into the same list.
+// It's safe for concurrent use.
+//
+// The assertions of a Handler fail the test it's bound to by New or
+// Attach.
+type Handler struct {
+	records *records
+	attrs   map[string]any
+	group   string
+	t       testing.TB
+}
+
+type records struct {
+	sync.Mutex
+	list []Record
+}
+
+func NewHandler() *Handler {
+	return &Handler{records: &records{}}
+}
+
+// New returns a Handler bound to t. If t fails, the recorded records are
+// logged on cleanup.
+func New(t testing.TB) *Handler {
+	if t == nil {
+		panic("logtest: nil testing.TB")
+	}
+	h := &Handler{records: &records{}, t: t}
+	t.Cleanup(func() {
+		if !t.Failed() {
+			return
+		}
+		var b strings.Builder
+		b.WriteString("logged records:")
+		for _, r := range h.Records() {
+			b.WriteString("\n\t" + r.String())
+		}
+		t.Log(b.String())
+	})
+	return h
+}
+
+// Attach returns a Handler bound to t, making it the handler of the
+// default loggers of the log and log/slog packages until cleanup. The
+// messages of the log package are recorded at the info level.
+//
+// The default loggers are process wide, so tests attaching handlers must
+// not run in parallel.
+func Attach(t testing.TB) *Handler {
+	h := New(t)
+	t.Cleanup(SetDefault(h))
+	return h
+}
+
+// SetDefault makes h the handler of the default loggers of the log and
+// log/slog packages, and returns a function restoring the previous ones.
+// The messages of the log package are recorded at the info level.
+func SetDefault(h *Handler) (restore func()) {
+	orig, w, flags := slog.Default(), log.Writer(), log.Flags()
+	slog.SetDefault(slog.New(h))
+	return func() {
+		slog.SetDefault(orig)
+		log.SetOutput(w)
+		log.SetFlags(flags)
+	}
+}
+
+// Records returns the recorded records in the order they were logged.
+func (h *Handler) Records() []Record {
+	h.records.Lock()
+	defer h.records.Unlock()
+	return slices.Clone(h.records.list)
+}
+
+// Reset discards the recorded records.
+func (h *Handler) Reset() {
+	h.records.Lock()
+	defer h.records.Unlock()
+	h.records.list = nil
+}
+
+func (h *Handler) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+func (h *Handler) Handle(_ context.Context, r slog.Record) error {
+	rec := Record{
+		Time:    r.Time,
+		Level:   r.Level,
+		Message: r.Message,
+		Attrs:   maps.Clone(h.attrs),
+	}
+	if rec.Attrs == nil {
+		rec.Attrs = map[string]any{}
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		flatten(rec.Attrs, h.group, a)
+		return true
+	})
+
+	h.records.Lock()
+	defer h.records.Unlock()
+	h.records.list = append(h.records.list, rec)
+	return nil
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	c := *h
+	c.attrs = maps.Clone(h.attrs)
+	if c.attrs == nil {
+		c.attrs = map[string]any{}
+	}
+	for _, a := range attrs {
+		flatten(c.attrs, h.group, a)
+	}
+	return &c
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	c := *h
+	c.group = h.group + name + "."
+	return &c
+}
+
+// flatten adds a to attrs, prefixing its key with group, following the
+// rules of slog.Handler: attributes with empty keys are dropped, as are
+// empty groups, and groups with empty keys are inlined.
+func flatten(attrs map[string]any, group string, a slog.Attr) {
+	v := a.Value.Resolve()
+	if v.Kind() != slog.KindGroup {
+		if a.Key != "" {
+			attrs[group+a.Key] = v.Any()
+		}
+		return
+	}
+	if a.Key != "" {
+		group += a.Key + "."
+	}
+	for _, a := range v.Group() {
+		flatten(attrs, group, a)
+	}
+}
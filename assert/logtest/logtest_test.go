@@ -0,0 +1,88 @@
+package logtest_test
+
+import (
+	"log"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/xandalm/go-testing/assert"
+	"github.com/xandalm/go-testing/assert/logtest"
+)
+
+type token string
+
+func (token) LogValue() slog.Value {
+	return slog.StringValue("***")
+}
+
+func TestHandler(t *testing.T) {
+	h := logtest.NewHandler()
+	logger := slog.New(h)
+
+	logger.Debug("start")
+	logger.With("svc", "api").WithGroup("req").With("id", 1).Info("served",
+		"status", 200,
+		slog.Group("", "inlined", true),
+		slog.Group("empty"),
+		slog.Group("user", "name", "alice", "token", token("secret")),
+		"", "no key",
+	)
+
+	records := h.Records()
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %v", records)
+	}
+	assert.Equal(t, records[0].Level, slog.LevelDebug)
+	assert.Equal(t, records[0].Message, "start")
+	assert.Empty(t, records[0].Attrs)
+	assert.Equal(t, records[1].Attrs, map[string]any{
+		"svc":            "api",
+		"req.id":         int64(1),
+		"req.status":     int64(200),
+		"req.inlined":    true,
+		"req.user.name":  "alice",
+		"req.user.token": "***",
+	})
+
+	h.Reset()
+	assert.Empty(t, h.Records())
+}
+
+func TestHandlerConcurrency(t *testing.T) {
+	h := logtest.NewHandler()
+	logger := slog.New(h).With("worker", true)
+
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			logger.Info("done", "i", i)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, len(h.Records()), 10)
+}
+
+func TestSetDefault(t *testing.T) {
+	orig, flags := slog.Default(), log.Flags()
+
+	h := logtest.NewHandler()
+	restore := logtest.SetDefault(h)
+	slog.Warn("from slog")
+	log.Print("from log")
+	restore()
+	slog.Debug("after restore")
+
+	records := h.Records()
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %v", records)
+	}
+	assert.Equal(t, records[1].Message, "from log")
+	assert.Equal(t, records[1].Level, slog.LevelInfo)
+	if slog.Default() != orig || log.Flags() != flags {
+		t.Error("default loggers should be restored")
+	}
+}
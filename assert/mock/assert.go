@@ -0,0 +1,98 @@
+package mock
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/xandalm/go-testing/assert"
+)
+
+// Spied is a spy of any type, as taken by CalledInOrder.
+type Spied interface {
+	fmt.Stringer
+	seqs() []uint64
+}
+
+// CalledTimes asserts the spy was called n times.
+func (s *Spy[A, R]) CalledTimes(t testing.TB, n int, out ...any) {
+	t.Helper()
+
+	if got := len(s.Calls()); got != n {
+		assert.Fail(t, assert.Failure{
+			Message:  fmt.Sprintf("expected %s to be called %d times, but got %d calls", s.name, n, got),
+			Expected: fmt.Sprint(n),
+			Actual:   fmt.Sprint(got),
+		}, out...)
+	}
+}
+
+// NotCalled asserts the spy wasn't called.
+func (s *Spy[A, R]) NotCalled(t testing.TB, out ...any) {
+	t.Helper()
+
+	if calls := s.Calls(); len(calls) > 0 {
+		assert.Fail(t, assert.Failure{
+			Message: fmt.Sprintf("expected %s not to be called, but got %d calls:%s", s.name, len(calls), formatCalls(calls)),
+		}, out...)
+	}
+}
+
+// CalledWith asserts the spy was called with args, compared as by
+// assert.Equal.
+func (s *Spy[A, R]) CalledWith(t testing.TB, args A, out ...any) {
+	t.Helper()
+
+	calls := s.Calls()
+	if slices.ContainsFunc(calls, func(c Call[A, R]) bool { return assert.Equivalent(c.Args, args) }) {
+		return
+	}
+	f := assert.Failure{Expected: assert.Format(args)}
+	if len(calls) == 0 {
+		f.Message = fmt.Sprintf("expected %s to be called with %s, but it wasn't called", s.name, f.Expected)
+	} else {
+		f.Message = fmt.Sprintf("expected %s to be called with %s, but got %d other calls:%s", s.name, f.Expected, len(calls), formatCalls(calls))
+	}
+	assert.Fail(t, f, out...)
+}
+
+func formatCalls[A, R any](calls []Call[A, R]) string {
+	var b strings.Builder
+	for _, c := range calls {
+		fmt.Fprintf(&b, "\n\t(%s) = %s", assert.Format(c.Args), assert.Format(c.Result))
+	}
+	return b.String()
+}
+
+// CalledInOrder asserts the spies were called in the given order: there's
+// a call of each spy made after a call of the spy before it.
+func CalledInOrder(t testing.TB, spies ...Spied) {
+	t.Helper()
+
+	var last uint64
+	for i, s := range spies {
+		seqs := s.seqs()
+		j := slices.IndexFunc(seqs, func(seq uint64) bool { return seq > last })
+		if j < 0 {
+			f := assert.Failure{Expected: names(spies)}
+			switch {
+			case len(seqs) == 0:
+				f.Message = fmt.Sprintf("expected calls in order %s, but %s wasn't called", f.Expected, s)
+			default:
+				f.Message = fmt.Sprintf("expected calls in order %s, but %s wasn't called after %s", f.Expected, s, spies[i-1])
+			}
+			assert.Fail(t, f)
+			return
+		}
+		last = seqs[j]
+	}
+}
+
+func names(spies []Spied) string {
+	s := make([]string, len(spies))
+	for i, spy := range spies {
+		s[i] = spy.String()
+	}
+	return strings.Join(s, ", ")
+}
@@ -0,0 +1,54 @@
+package mock_test
+
+import (
+	"testing"
+
+	"github.com/xandalm/go-testing/assert/asserttest"
+	"github.com/xandalm/go-testing/assert/mock"
+)
+
+func TestAssertions(t *testing.T) {
+	open := mock.New[string, error]("open", nil)
+	read := mock.New[int, []byte]("read", nil).Return([]byte("hi"))
+	closer := mock.New[struct{}, error]("close", nil)
+	unused := mock.New[int, int]("unused", nil)
+
+	open.Call("a.txt")
+	read.Call(2)
+	closer.Call(struct{}{})
+
+	asserttest.ExpectSuccess(t, func(t testing.TB) {
+		open.CalledTimes(t, 1)
+		open.CalledWith(t, "a.txt")
+		unused.NotCalled(t)
+		mock.CalledInOrder(t, open, read, closer)
+		mock.CalledInOrder(t, open, closer)
+	})
+
+	cases := []struct {
+		name string
+		fn   func(t testing.TB)
+		want string
+	}{
+		{"CalledTimes", func(t testing.TB) { read.CalledTimes(t, 2) },
+			"expected read to be called 2 times, but got 1 calls"},
+		{"CalledWith", func(t testing.TB) { read.CalledWith(t, 3) },
+			"expected read to be called with 3, but got 1 other calls:\n\t(2) = []uint8(\"hi\")"},
+		{"CalledWith none", func(t testing.TB) { unused.CalledWith(t, 3) },
+			"expected unused to be called with 3, but it wasn't called"},
+		{"NotCalled", func(t testing.TB) { open.NotCalled(t) },
+			"expected open not to be called, but got 1 calls:\n\t(\"a.txt\") = nil"},
+		{"CalledInOrder", func(t testing.TB) { mock.CalledInOrder(t, read, open) },
+			"expected calls in order read, open, but open wasn't called after read"},
+		{"CalledInOrder not called", func(t testing.TB) { mock.CalledInOrder(t, open, unused) },
+			"expected calls in order open, unused, but unused wasn't called"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := asserttest.ExpectFailure(t, c.fn)
+			if got := r.Messages("Fatal"); len(got) != 1 || got[0] != c.want {
+				t.Errorf("expected message %q, got %q", c.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package mock
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/xandalm/go-testing/assert"
+)
+
+// Expectations records the calls expected of a mock and the calls made
+// to it. It's the state of the mocks generated by the cmd command, which
+// wrap it with methods typed after the mocked interface.
+type Expectations struct {
+	name string
+
+	mu         sync.Mutex
+	expected   []*Expected
+	calls      []string
+	unexpected []string
+}
+
+// NewExpectations returns the expectations of a mock of the named
+// interface.
+func NewExpectations(name string) *Expectations {
+	return &Expectations{name: name}
+}
+
+// Expected is a call expected of a mock.
+type Expected struct {
+	mu      *sync.Mutex
+	method  string
+	args    []any
+	anyArgs bool
+	results []any
+	times   int
+	calls   int
+}
+
+// Expect expects a call of method with args, compared as by assert.Equal,
+// once unless set otherwise by Times.
+func (e *Expectations) Expect(method string, args ...any) *Expected {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	x := &Expected{mu: &e.mu, method: method, args: args, times: 1}
+	e.expected = append(e.expected, x)
+	return x
+}
+
+// Return sets the results of the call.
+func (x *Expected) Return(results ...any) *Expected {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.results = results
+	return x
+}
+
+// Times sets the number of calls expected, or any number if n is negative.
+func (x *Expected) Times(n int) *Expected {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.times = n
+	return x
+}
+
+// AnyArgs makes calls with any arguments match.
+func (x *Expected) AnyArgs() *Expected {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.anyArgs = true
+	return x
+}
+
+func (x *Expected) matches(method string, args []any) bool {
+	if x.method != method || (x.times >= 0 && x.calls >= x.times) {
+		return false
+	}
+	return x.anyArgs || assert.Equivalent(x.args, args)
+}
+
+// Call records a call of method with args and returns the results of the
+// first expectation it matches, in the order they were set, which still
+// expects calls. Calls matching none are reported by Verify and return
+// nil results.
+func (e *Expectations) Call(method string, args ...any) []any {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	call := formatCall(method, args)
+	e.calls = append(e.calls, call)
+	for _, x := range e.expected {
+		if x.matches(method, args) {
+			x.calls++
+			return x.results
+		}
+	}
+	e.unexpected = append(e.unexpected, call)
+	return nil
+}
+
+// Calls returns the calls made, formatted as "Method(args...)", in the
+// order they were made.
+func (e *Expectations) Calls() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]string(nil), e.calls...)
+}
+
+// Verify asserts the expected calls were made, and no others.
+func (e *Expectations) Verify(t testing.TB) {
+	t.Helper()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var b strings.Builder
+	n := 0
+	for _, x := range e.expected {
+		if x.times >= 0 && x.calls != x.times {
+			fmt.Fprintf(&b, "\n\t%s: called %d of %d times", x, x.calls, x.times)
+			n++
+		}
+	}
+	for _, call := range e.unexpected {
+		fmt.Fprintf(&b, "\n\t%s: unexpected", call)
+		n++
+	}
+	if n > 0 {
+		assert.Fail(t, assert.Failure{
+			Message: fmt.Sprintf("expected calls of %s weren't made as expected, %d mismatches:%s", e.name, n, b.String()),
+		})
+	}
+}
+
+func (x *Expected) String() string {
+	if x.anyArgs {
+		return x.method + "(...)"
+	}
+	return formatCall(x.method, x.args)
+}
+
+func formatCall(method string, args []any) string {
+	s := make([]string, len(args))
+	for i, arg := range args {
+		s[i] = assert.Format(arg)
+	}
+	return method + "(" + strings.Join(s, ", ") + ")"
+}
@@ -0,0 +1,53 @@
+package mock_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/xandalm/go-testing/assert"
+	"github.com/xandalm/go-testing/assert/asserttest"
+	"github.com/xandalm/go-testing/assert/mock"
+)
+
+func TestExpectations(t *testing.T) {
+	errMissing := errors.New("missing")
+
+	asserttest.ExpectSuccess(t, func(t testing.TB) {
+		e := mock.NewExpectations("Store")
+		e.Expect("Get", "a").Return(1, nil)
+		e.Expect("Get", "b").Return(0, errMissing).Times(2)
+		e.Expect("Put").AnyArgs().Times(-1)
+
+		assert.Equal(t, e.Call("Get", "b"), []any{0, errMissing})
+		assert.Equal(t, e.Call("Get", "a"), []any{1, nil})
+		assert.Equal(t, e.Call("Get", "b"), []any{0, errMissing})
+		assert.Equal(t, e.Call("Put", "c", 3), nil)
+		assert.Equal(t, e.Calls(), []string{`Get("b")`, `Get("a")`, `Get("b")`, `Put("c", 3)`})
+
+		// arguments are compared as by assert.Equal
+		now := time.Now()
+		e.Expect("Expire", now).Return(true)
+		assert.Equal(t, e.Call("Expire", now.UTC()), []any{true})
+		e.Verify(t)
+	})
+
+	r := asserttest.ExpectFailure(t, func(t testing.TB) {
+		e := mock.NewExpectations("Store")
+		e.Expect("Get", "a").Return(1, nil)
+		e.Expect("Put").AnyArgs()
+		e.Expect("Delete", "a").Times(2)
+
+		e.Call("Get", "a")
+		e.Call("Get", "a")
+		e.Call("Delete", "a")
+		e.Verify(t)
+	})
+	want := "expected calls of Store weren't made as expected, 3 mismatches:" +
+		"\n\tPut(...): called 0 of 1 times" +
+		"\n\tDelete(\"a\"): called 1 of 2 times" +
+		"\n\tGet(\"a\"): unexpected"
+	if got := r.Messages("Fatal"); len(got) != 1 || got[0] != want {
+		t.Errorf("expected message %q, got %q", want, got)
+	}
+}
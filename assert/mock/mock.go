@@ -0,0 +1,166 @@
+// Package mock provides spies: functions recording their calls, which can
+// stand in for a dependency of the code under test or wrap a real one. It
+// also holds the expectations of the mocks generated by the cmd command.
+package mock
+
+import (
+	"bytes"
+	"cmp"
+	"runtime"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/xandalm/go-testing/assert"
+)
+
+// Call is a call made to a spy.
+type Call[A, R any] struct {
+	Args   A
+	Result R
+	// Goroutine is the id of the goroutine making the call.
+	Goroutine uint64
+	Time      time.Time
+
+	seq uint64
+}
+
+// seq orders the calls made to all spies.
+var seq atomic.Uint64
+
+type response[A, R any] struct {
+	args   A
+	result R
+}
+
+// Spy records the calls made to a function taking A and returning R.
+// Functions with several arguments or results are spied with A and R
+// being structs of them.
+//
+// The result of a call is, in order of precedence, the one given by On for
+// its arguments, the next one queued by Return, the result of the wrapped
+// function or else the zero R. A Spy is safe for concurrent use.
+type Spy[A, R any] struct {
+	name string
+	fn   func(A) R
+
+	mu        sync.Mutex
+	calls     []Call[A, R]
+	queue     []R
+	responses []response[A, R]
+}
+
+// New returns a spy named name, as reported in failures, wrapping fn. If
+// fn is nil, the spy is a stub returning the results it's given.
+func New[A, R any](name string, fn func(A) R) *Spy[A, R] {
+	return &Spy[A, R]{name: name, fn: fn}
+}
+
+// Func returns the spy as a function, to be given to the code under test.
+func (s *Spy[A, R]) Func() func(A) R {
+	return s.Call
+}
+
+// Call calls the spy with args, recording the call.
+func (s *Spy[A, R]) Call(args A) R {
+	s.mu.Lock()
+	n := seq.Add(1)
+	s.calls = append(s.calls, Call[A, R]{
+		Args:      args,
+		Goroutine: goroutine(),
+		Time:      time.Now(),
+		seq:       n,
+	})
+	result, ok := s.result(args)
+	fn := s.fn
+	s.mu.Unlock()
+
+	if !ok && fn != nil {
+		result = fn(args)
+	}
+
+	// The call is found again by its sequence number, as calls may have
+	// been made or forgotten by Reset meanwhile. Being taken under s.mu,
+	// the sequence numbers of the calls are increasing.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if i, found := slices.BinarySearchFunc(s.calls, n, func(c Call[A, R], n uint64) int {
+		return cmp.Compare(c.seq, n)
+	}); found {
+		s.calls[i].Result = result
+	}
+	return result
+}
+
+func (s *Spy[A, R]) result(args A) (R, bool) {
+	for _, r := range s.responses {
+		if assert.Equivalent(r.args, args) {
+			return r.result, true
+		}
+	}
+	if len(s.queue) > 0 {
+		result := s.queue[0]
+		s.queue = s.queue[1:]
+		return result, true
+	}
+	var zero R
+	return zero, false
+}
+
+// Return queues results, returned by the next calls in turn.
+func (s *Spy[A, R]) Return(results ...R) *Spy[A, R] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queue = append(s.queue, results...)
+	return s
+}
+
+// On makes the calls with args, compared as by assert.Equal, return
+// result.
+func (s *Spy[A, R]) On(args A, result R) *Spy[A, R] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.responses = append(s.responses, response[A, R]{args, result})
+	return s
+}
+
+// Calls returns the calls made so far, in the order they were made.
+func (s *Spy[A, R]) Calls() []Call[A, R] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.calls)
+}
+
+// Reset forgets the calls made so far, keeping the results given.
+func (s *Spy[A, R]) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = nil
+}
+
+func (s *Spy[A, R]) String() string {
+	return s.name
+}
+
+func (s *Spy[A, R]) seqs() []uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	seqs := make([]uint64, len(s.calls))
+	for i, c := range s.calls {
+		seqs[i] = c.seq
+	}
+	return seqs
+}
+
+// goroutine returns the id of the running goroutine, as found in the
+// header of its stack trace.
+func goroutine() uint64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	b, _, _ = bytes.Cut(b, []byte(" "))
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
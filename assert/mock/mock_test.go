@@ -0,0 +1,93 @@
+package mock_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xandalm/go-testing/assert"
+	"github.com/xandalm/go-testing/assert/asserttest"
+	"github.com/xandalm/go-testing/assert/mock"
+)
+
+func TestSpy(t *testing.T) {
+	t.Run("wraps", func(t *testing.T) {
+		upper := mock.New("upper", strings.ToUpper)
+		before := time.Now()
+		assert.Equal(t, upper.Func()("go"), "GO")
+
+		calls := upper.Calls()
+		assert.Equal(t, len(calls), 1)
+		assert.Equal(t, calls[0].Args, "go")
+		assert.Equal(t, calls[0].Result, "GO")
+		assert.True(t, !calls[0].Time.Before(before))
+		assert.NotZero(t, calls[0].Goroutine)
+	})
+
+	t.Run("stubs", func(t *testing.T) {
+		s := mock.New[int, string]("stub", nil).Return("a", "b").On(7, "seven")
+		got := []string{s.Call(1), s.Call(7), s.Call(2), s.Call(3)}
+		assert.Equal(t, got, []string{"a", "seven", "b", ""})
+	})
+
+	t.Run("overrides wrapped", func(t *testing.T) {
+		s := mock.New("double", func(n int) int { return 2 * n }).On(3, 0).Return(-1)
+		got := []int{s.Call(3), s.Call(1), s.Call(1)}
+		assert.Equal(t, got, []int{0, -1, 2})
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		s := mock.New[int, int]("s", nil).Return(5)
+		s.Call(1)
+		s.Reset()
+		assert.Equal(t, len(s.Calls()), 0)
+	})
+
+	t.Run("time arguments", func(t *testing.T) {
+		now := time.Now()
+		s := mock.New[time.Time, string]("format", nil).On(now, "now")
+		assert.Equal(t, s.Call(now.In(time.FixedZone("UTC-3", -3*60*60))), "now")
+		asserttest.ExpectSuccess(t, func(t testing.TB) {
+			s.CalledWith(t, now.UTC())
+		})
+	})
+
+	t.Run("reset during call", func(t *testing.T) {
+		var s *mock.Spy[int, int]
+		s = mock.New("tenfold", func(n int) int {
+			if n == 1 {
+				s.Reset()
+				s.Call(2)
+			}
+			return 10 * n
+		})
+		s.Call(1)
+
+		calls := s.Calls()
+		assert.Equal(t, len(calls), 1)
+		assert.Equal(t, calls[0].Args, 2)
+		assert.Equal(t, calls[0].Result, 20)
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		s := mock.New("id", func(n int) int { return n })
+		var wg sync.WaitGroup
+		for i := range 50 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s.Call(i)
+			}()
+		}
+		wg.Wait()
+
+		goroutines := map[uint64]bool{}
+		for _, c := range s.Calls() {
+			assert.Equal(t, c.Result, c.Args)
+			goroutines[c.Goroutine] = true
+		}
+		assert.Equal(t, len(s.Calls()), 50)
+		assert.Equal(t, len(goroutines), 50)
+	})
+}
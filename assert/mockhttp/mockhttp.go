@@ -0,0 +1,362 @@
+// Package mockhttp provides an HTTP server answering the requests tests
+// expect of it with canned responses, to stand in for the services a
+// program under test calls:
+//
+//	s := mockhttp.NewServer(t)
+//	s.Expect("GET", "/users/*").
+//		Header("Authorization", httpassert.Matches(`^Bearer `)).
+//		RespondJSON(http.StatusOK, user)
+//	launcher.WithEnv(s.Env("USERS_URL"))
+//	...
+//	s.Verify(t)
+package mockhttp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tpkg "github.com/xandalm/go-testing"
+	"github.com/xandalm/go-testing/assert"
+	"github.com/xandalm/go-testing/assert/httpassert"
+)
+
+// Server is an httptest.Server answering the expected requests. Requests
+// matching no expectation get a 404 response, and are reported by Verify.
+type Server struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	expected   []*Expectation
+	requests   []string
+	unexpected []unexpected
+}
+
+// unexpected is a request matching no expectation, with the mismatches of
+// the closest expectation, if any.
+type unexpected struct {
+	request    string
+	closest    *Expectation
+	mismatches []string
+}
+
+// NewServer starts a server, closed when the test ends.
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+
+	s := new(Server)
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Checker returns a checker pinging the server, to wait for it as for a
+// launched server. The pings are requests as others, to be expected.
+func (s *Server) Checker() *tpkg.HTTPServerChecker {
+	return &tpkg.HTTPServerChecker{BaseURL: s.URL, Cli: s.Client()}
+}
+
+// Env returns the environment variable key set to the URL of the server,
+// as "key=URL", to give a launched server.
+func (s *Server) Env(key string) string {
+	return key + "=" + s.URL
+}
+
+// Expectation is a request expected by a server, and its response.
+type Expectation struct {
+	mu      *sync.Mutex
+	method  string
+	pattern string
+	query   []valueCheck
+	header  []valueCheck
+	json    *string
+	jsonDoc any
+
+	status     int
+	respHeader http.Header
+	body       []byte
+	delay      time.Duration
+	times      int
+	calls      int
+}
+
+type valueCheck struct {
+	key string
+	m   httpassert.Matcher
+}
+
+// Expect expects a request with method whose path matches pattern, as
+// path.Match does, once unless set otherwise by Times. Its response is an
+// empty 200 unless set otherwise.
+func (s *Server) Expect(method, pattern string) *Expectation {
+	if _, err := path.Match(pattern, ""); err != nil {
+		panic("mockhttp: invalid path pattern " + pattern)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	x := &Expectation{
+		mu:         &s.mu,
+		method:     method,
+		pattern:    pattern,
+		status:     http.StatusOK,
+		respHeader: http.Header{},
+		times:      1,
+	}
+	s.expected = append(s.expected, x)
+	return x
+}
+
+// Query expects the query parameter key to have a value matched by m.
+func (x *Expectation) Query(key string, m httpassert.Matcher) *Expectation {
+	if m == nil {
+		panic("mockhttp: nil matcher")
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.query = append(x.query, valueCheck{key, m})
+	return x
+}
+
+// Header expects the header key to have a value matched by m.
+func (x *Expectation) Header(key string, m httpassert.Matcher) *Expectation {
+	if m == nil {
+		panic("mockhttp: nil matcher")
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.header = append(x.header, valueCheck{http.CanonicalHeaderKey(key), m})
+	return x
+}
+
+// JSON expects a body equivalent to the JSON document want, regardless of
+// spacing and of the order of object keys.
+func (x *Expectation) JSON(want string) *Expectation {
+	var doc any
+	if err := json.Unmarshal([]byte(want), &doc); err != nil {
+		panic("mockhttp: invalid expected JSON: " + err.Error())
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.json, x.jsonDoc = &want, doc
+	return x
+}
+
+// Respond sets the status and the body of the response.
+func (x *Expectation) Respond(status int, body string) *Expectation {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.status, x.body = status, []byte(body)
+	return x
+}
+
+// RespondJSON sets the status of the response and its body to v encoded as
+// JSON, and the content type accordingly.
+func (x *Expectation) RespondJSON(status int, v any) *Expectation {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic("mockhttp: cannot encode JSON body: " + err.Error())
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.status, x.body = status, b
+	x.respHeader.Set("Content-Type", "application/json")
+	return x
+}
+
+// ResponseHeader adds the header key with value to the response.
+func (x *Expectation) ResponseHeader(key, value string) *Expectation {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.respHeader.Add(key, value)
+	return x
+}
+
+// Delay delays the response by d, or until the client gives up.
+func (x *Expectation) Delay(d time.Duration) *Expectation {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.delay = d
+	return x
+}
+
+// Times sets the number of requests expected, or any number if n is
+// negative.
+func (x *Expectation) Times(n int) *Expectation {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.times = n
+	return x
+}
+
+func (x *Expectation) String() string {
+	return x.method + " " + x.pattern
+}
+
+// request is a request received, its body read.
+type request struct {
+	*http.Request
+	body []byte
+}
+
+func (r request) String() string {
+	return r.Method + " " + r.URL.RequestURI()
+}
+
+// mismatches returns how r doesn't match x, nothing if it does.
+func (x *Expectation) mismatches(r request) []string {
+	var ms []string
+	if r.Method != x.method {
+		ms = append(ms, fmt.Sprintf("expected method %s, but got %s", x.method, r.Method))
+	}
+	if ok, _ := path.Match(x.pattern, r.URL.Path); !ok {
+		ms = append(ms, fmt.Sprintf("expected path to match %q, but got %q", x.pattern, r.URL.Path))
+	}
+	query := r.URL.Query()
+	for _, c := range x.query {
+		if values := query[c.key]; !matchAny(c.m, values) {
+			ms = append(ms, fmt.Sprintf("expected query %q %v, but got %s", c.key, c.m, formatValues(values)))
+		}
+	}
+	for _, c := range x.header {
+		if values := r.Header.Values(c.key); !matchAny(c.m, values) {
+			ms = append(ms, fmt.Sprintf("expected header %q %v, but got %s", c.key, c.m, formatValues(values)))
+		}
+	}
+	if x.json != nil {
+		var doc any
+		if err := json.Unmarshal(r.body, &doc); err != nil || !reflect.DeepEqual(doc, x.jsonDoc) {
+			ms = append(ms, fmt.Sprintf("expected JSON body %s, but got %q", *x.json, r.body))
+		}
+	}
+	return ms
+}
+
+func matchAny(m httpassert.Matcher, values []string) bool {
+	for _, v := range values {
+		if m.Match(v) {
+			return true
+		}
+	}
+	return false
+}
+
+func formatValues(values []string) string {
+	switch len(values) {
+	case 0:
+		return "no value"
+	case 1:
+		return fmt.Sprintf("%q", values[0])
+	default:
+		return fmt.Sprintf("%q", values)
+	}
+}
+
+// match returns the first expectation, in the order they were set, which
+// matches r and still expects requests. Otherwise, it records r as
+// unexpected, with the closest expectation: the one it mismatches the
+// least.
+func (s *Server) match(r request) *Expectation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, r.String())
+	u := unexpected{request: r.String()}
+	for _, x := range s.expected {
+		ms := x.mismatches(r)
+		if len(ms) == 0 {
+			if x.times < 0 || x.calls < x.times {
+				x.calls++
+				return x
+			}
+			ms = []string{fmt.Sprintf("expected %d requests, but got more", x.times)}
+		}
+		if u.closest == nil || len(ms) < len(u.mismatches) {
+			u.closest, u.mismatches = x, ms
+		}
+	}
+	s.unexpected = append(s.unexpected, u)
+	return nil
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "mockhttp: cannot read body, "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	req := request{r, body}
+	x := s.match(req)
+	if x == nil {
+		http.Error(w, "mockhttp: unexpected request "+req.String(), http.StatusNotFound)
+		return
+	}
+
+	s.mu.Lock()
+	status, header, resBody, delay := x.status, x.respHeader.Clone(), x.body, x.delay
+	s.mu.Unlock()
+
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-r.Context().Done():
+			return
+		}
+	}
+	for key, values := range header {
+		w.Header()[key] = values
+	}
+	w.WriteHeader(status)
+	w.Write(resBody)
+}
+
+// Requests returns the requests received, formatted as "METHOD URI", in
+// the order they were received.
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+// Verify asserts the expected requests were received, and no others. Each
+// unexpected request is shown with how it mismatches the closest
+// expectation.
+func (s *Server) Verify(t testing.TB) {
+	t.Helper()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var b strings.Builder
+	n := 0
+	for _, x := range s.expected {
+		if x.times >= 0 && x.calls != x.times {
+			fmt.Fprintf(&b, "\n\t%s: received %d of %d times", x, x.calls, x.times)
+			n++
+		}
+	}
+	for _, u := range s.unexpected {
+		fmt.Fprintf(&b, "\n\t%s: unexpected", u.request)
+		if u.closest != nil {
+			fmt.Fprintf(&b, ", closest to %s:", u.closest)
+			for _, m := range u.mismatches {
+				fmt.Fprintf(&b, "\n\t\t%s", m)
+			}
+		}
+		n++
+	}
+	if n > 0 {
+		assert.Fail(t, assert.Failure{
+			Message: fmt.Sprintf("expected requests weren't received as expected, %d mismatches:%s", n, b.String()),
+		})
+	}
+}
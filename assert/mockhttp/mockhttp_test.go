@@ -0,0 +1,111 @@
+package mockhttp_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xandalm/go-testing/assert"
+	"github.com/xandalm/go-testing/assert/asserttest"
+	"github.com/xandalm/go-testing/assert/httpassert"
+	"github.com/xandalm/go-testing/assert/mockhttp"
+)
+
+func do(t testing.TB, s *mockhttp.Server, method, target, body string, header ...string) (*http.Response, string) {
+	t.Helper()
+
+	req, err := http.NewRequest(method, s.URL+target, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Add(header[i], header[i+1])
+	}
+	res, err := s.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res, string(b)
+}
+
+func TestServer(t *testing.T) {
+	s := mockhttp.NewServer(t)
+	s.Expect("GET", "/users/*").
+		Query("fields", httpassert.Equals("name")).
+		Header("Authorization", httpassert.Matches(`^Bearer `)).
+		RespondJSON(http.StatusOK, map[string]string{"name": "gopher"})
+	s.Expect("POST", "/users").
+		JSON(`{"name": "gopher", "admin": false}`).
+		Respond(http.StatusCreated, "created").
+		ResponseHeader("Location", "/users/1")
+	s.Expect("DELETE", "/users/*").Times(2)
+
+	res, body := do(t, s, "GET", "/users/1?fields=name", "", "Authorization", "Bearer token")
+	assert.Equal(t, res.StatusCode, http.StatusOK)
+	assert.Equal(t, res.Header.Get("Content-Type"), "application/json")
+	assert.Equal(t, body, `{"name":"gopher"}`)
+
+	res, body = do(t, s, "POST", "/users", `{"admin":false,"name":"gopher"}`)
+	assert.Equal(t, res.StatusCode, http.StatusCreated)
+	assert.Equal(t, res.Header.Get("Location"), "/users/1")
+	assert.Equal(t, body, "created")
+
+	do(t, s, "DELETE", "/users/1", "")
+	do(t, s, "DELETE", "/users/2", "")
+
+	assert.Equal(t, s.Requests(), []string{"GET /users/1?fields=name", "POST /users", "DELETE /users/1", "DELETE /users/2"})
+	asserttest.ExpectSuccess(t, s.Verify)
+}
+
+func TestServerUnexpected(t *testing.T) {
+	s := mockhttp.NewServer(t)
+	s.Expect("GET", "/users/*").Header("Accept", httpassert.Equals("application/json"))
+	s.Expect("POST", "/users").JSON(`{"name": "gopher"}`)
+	s.Expect("DELETE", "/users/*")
+
+	res, body := do(t, s, "POST", "/users", `{"name": "alice"}`)
+	assert.Equal(t, res.StatusCode, http.StatusNotFound)
+	assert.Equal(t, body, "mockhttp: unexpected request POST /users\n")
+	do(t, s, "DELETE", "/users/1", "")
+	do(t, s, "DELETE", "/users/2", "")
+
+	r := asserttest.ExpectFailure(t, s.Verify)
+	assert.Equal(t, r.Messages("Fatal"), []string{
+		"expected requests weren't received as expected, 4 mismatches:" +
+			"\n\tGET /users/*: received 0 of 1 times" +
+			"\n\tPOST /users: received 0 of 1 times" +
+			"\n\tPOST /users: unexpected, closest to POST /users:" +
+			"\n\t\texpected JSON body {\"name\": \"gopher\"}, but got \"{\\\"name\\\": \\\"alice\\\"}\"" +
+			"\n\tDELETE /users/2: unexpected, closest to DELETE /users/*:" +
+			"\n\t\texpected 1 requests, but got more",
+	})
+}
+
+func TestServerDelay(t *testing.T) {
+	s := mockhttp.NewServer(t)
+	s.Expect("GET", "/slow").Delay(time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", s.URL+"/slow", nil)
+	_, err := s.Client().Do(req)
+	assert.NotNil(t, err)
+}
+
+func TestServerChecker(t *testing.T) {
+	s := mockhttp.NewServer(t)
+	s.Expect("GET", "/")
+
+	c := s.Checker()
+	assert.Nil(t, c.Ping())
+	assert.Equal(t, s.Env("API_URL"), "API_URL="+s.URL)
+	asserttest.ExpectSuccess(t, s.Verify)
+}
@@ -0,0 +1,226 @@
+package assert
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Failure describes a failed assertion.
+type Failure struct {
+	Test      string `json:"test"`
+	Assertion string `json:"assertion"`
+	File      string `json:"file"`
+	Line      int    `json:"line"`
+	Message   string `json:"message"`
+	Expected  string `json:"expected,omitempty"`
+	Actual    string `json:"actual,omitempty"`
+	Diff      string `json:"diff,omitempty"`
+}
+
+// Reporter renders failures into the text the failing test prints.
+type Reporter interface {
+	Report(f Failure) string
+}
+
+// ReporterEnv is the environment variable selecting the default reporter:
+// "text", "color" or "json", the latter writing to the standard output.
+// When unset, failures are colored if the standard output is a terminal
+// and NO_COLOR isn't set.
+const ReporterEnv = "ASSERT_REPORTER"
+
+var reporter = struct {
+	sync.RWMutex
+	r Reporter
+}{}
+
+// SetReporter makes r report the failures of every assertion. A nil r
+// restores the reporter selected by the environment.
+func SetReporter(r Reporter) {
+	reporter.Lock()
+	defer reporter.Unlock()
+	reporter.r = r
+}
+
+func currentReporter() Reporter {
+	reporter.RLock()
+	defer reporter.RUnlock()
+	if reporter.r != nil {
+		return reporter.r
+	}
+	return envReporter()
+}
+
+func envReporter() Reporter {
+	switch os.Getenv(ReporterEnv) {
+	case "json":
+		return JSONReporter{}
+	case "color":
+		return ColorReporter{}
+	case "text":
+		return TextReporter{}
+	}
+	if _, ok := os.LookupEnv("NO_COLOR"); !ok && isTerminal(os.Stdout) {
+		return ColorReporter{}
+	}
+	return TextReporter{}
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// TextReporter reports the failure message followed by the differences
+// between the compared values, if any.
+type TextReporter struct{}
+
+func (TextReporter) Report(f Failure) string {
+	if f.Diff == "" {
+		return f.Message
+	}
+	return f.Message + "\ndifferences:" + f.Diff
+}
+
+// ColorReporter reports as TextReporter, using ANSI colors to tell the
+// expected values (green) from the actual ones (red).
+type ColorReporter struct{}
+
+const (
+	ansiRed   = "\x1b[31m"
+	ansiGreen = "\x1b[32m"
+	ansiBold  = "\x1b[1m"
+	ansiReset = "\x1b[0m"
+)
+
+func (ColorReporter) Report(f Failure) string {
+	var b strings.Builder
+	b.WriteString(ansiBold + f.Message + ansiReset)
+	if f.Expected != "" {
+		fmt.Fprintf(&b, "\nexpected: %s%s%s", ansiGreen, f.Expected, ansiReset)
+	}
+	if f.Actual != "" {
+		fmt.Fprintf(&b, "\nactual:   %s%s%s", ansiRed, f.Actual, ansiReset)
+	}
+	if f.Diff != "" {
+		b.WriteString("\ndifferences:")
+		for _, line := range strings.Split(f.Diff, "\n")[1:] {
+			a, e, ok := strings.Cut(line, " != ")
+			if !ok {
+				b.WriteString("\n" + line)
+				continue
+			}
+			path, actual, _ := strings.Cut(a, ": ")
+			fmt.Fprintf(&b, "\n%s: %s%s%s != %s%s%s", path, ansiRed, actual, ansiReset, ansiGreen, e, ansiReset)
+		}
+	}
+	return b.String()
+}
+
+// JSONReporter writes each failure as a single line of JSON to W, or to
+// the standard output if W is nil, for tools reading the test output. The
+// failing test prints the failure as reported by TextReporter, as the
+// testing package prefixes and indents what it prints.
+type JSONReporter struct {
+	W io.Writer
+}
+
+// jsonMu keeps the lines of failures reported concurrently apart.
+var jsonMu sync.Mutex
+
+func (r JSONReporter) Report(f Failure) string {
+	if b, err := json.Marshal(f); err == nil {
+		w := r.W
+		if w == nil {
+			w = os.Stdout
+		}
+		jsonMu.Lock()
+		w.Write(append(b, '\n'))
+		jsonMu.Unlock()
+	}
+	return TextReporter{}.Report(f)
+}
+
+// Fail fails t with f rendered by the current reporter, as the assertions
+// of this package do. It fills the test name, the location and, if
+// missing, the assertion name of f. The out arguments, if given, replace
+// the message of f as in the assertions.
+func Fail(t testing.TB, f Failure, out ...any) {
+	t.Helper()
+	report(t, f, out)
+}
+
+func report(t testing.TB, f Failure, out []any) {
+	t.Helper()
+
+	if len(out) > 0 {
+		str, ok := out[0].(string)
+		if !ok {
+			panic("assert: output argument must be a format string and its args")
+		}
+		f.Message = fmt.Sprintf(str, out[1:]...)
+	}
+	entry, frame := callSite()
+	if f.Assertion == "" {
+		f.Assertion = assertionName(entry)
+	}
+	f.Test = t.Name()
+	f.File, f.Line = filepath.Base(frame.File), frame.Line
+	t.Fatal(currentReporter().Report(f))
+}
+
+// callSite returns the first frame outside this package and its
+// subpackages, and the assertion it called.
+func callSite() (entry string, site runtime.Frame) {
+	var pcs [50]uintptr
+	n := runtime.Callers(2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !isAssertion(frame.Function) {
+			return entry, frame
+		}
+		entry = frame.Function
+		if !more {
+			return entry, runtime.Frame{}
+		}
+	}
+}
+
+// isAssertion reports whether fn belongs to this package or, not being a
+// test, to one of its subpackages.
+func isAssertion(fn string) bool {
+	if strings.HasPrefix(fn, pkgPrefix) {
+		return true
+	}
+	subpkgs := strings.TrimSuffix(pkgPrefix, ".") + "/"
+	if !strings.HasPrefix(fn, subpkgs) {
+		return false
+	}
+	pkg := fn[:strings.LastIndexByte(fn, '/')+1]
+	pkg += strings.SplitN(fn[len(pkg):], ".", 2)[0]
+	return !strings.HasSuffix(pkg, "_test")
+}
+
+// assertionName returns the name of the assertion from the name of the
+// function implementing it, e.g. "Equal" from "pkg.Equal[...]" or
+// "pkg.(*Assert).Equal".
+func assertionName(fn string) string {
+	name := fn[strings.LastIndexByte(fn, '/')+1:]
+	if i := strings.IndexByte(name, '.'); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.IndexByte(name, '['); i >= 0 {
+		name = name[:i]
+	}
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
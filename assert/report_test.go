@@ -0,0 +1,112 @@
+package assert_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	tpkg "github.com/xandalm/go-testing"
+	"github.com/xandalm/go-testing/assert"
+)
+
+func useReporter(t *testing.T, r assert.Reporter) {
+	t.Helper()
+	assert.SetReporter(r)
+	t.Cleanup(func() {
+		assert.SetReporter(nil)
+	})
+}
+
+func TestJSONReporter(t *testing.T) {
+	var buf bytes.Buffer
+	useReporter(t, assert.JSONReporter{W: &buf})
+
+	got := failure(t, func(t testing.TB) {
+		assert.Equal(t, pointer{1, 2}, pointer{1, 3})
+	})
+	if want := "expected equal values, but got assert_test.pointer{X: 1, Y: 2} and assert_test.pointer{X: 1, Y: 3}\ndifferences:\n\t.Y: 2 != 3"; got != want {
+		t.Errorf("test should print the text failure, got %q", got)
+	}
+
+	line, ok := strings.CutSuffix(buf.String(), "\n")
+	if !ok || strings.Contains(line, "\n") {
+		t.Fatalf("failure should be written as a single line, got %q", buf.String())
+	}
+	var f assert.Failure
+	if err := json.Unmarshal([]byte(line), &f); err != nil {
+		t.Fatalf("failure isn't a JSON line: %v\n%s", err, line)
+	}
+	want := assert.Failure{
+		Test:      t.Name(),
+		Assertion: "Equal",
+		File:      "report_test.go",
+		Line:      f.Line,
+		Message:   "expected equal values, but got assert_test.pointer{X: 1, Y: 2} and assert_test.pointer{X: 1, Y: 3}",
+		Expected:  "assert_test.pointer{X: 1, Y: 3}",
+		Actual:    "assert_test.pointer{X: 1, Y: 2}",
+		Diff:      "\n\t.Y: 2 != 3",
+	}
+	if f != want {
+		t.Errorf("got %+v\nwant %+v", f, want)
+	}
+	if f.Line == 0 {
+		t.Error("line of the failure should be reported")
+	}
+}
+
+func TestColorReporter(t *testing.T) {
+	useReporter(t, assert.ColorReporter{})
+
+	got := failure(t, func(t testing.TB) {
+		assert.EqualOn(assert.New(t), []int{1, 2}, []int{1, 3})
+	})
+	for _, want := range []string{
+		"\x1b[1mexpected equal values",
+		"expected: \x1b[32m[]int{1, 3}\x1b[0m",
+		"actual:   \x1b[31m[]int{1, 2}\x1b[0m",
+		"\t[1]: \x1b[31m2\x1b[0m != \x1b[32m3\x1b[0m",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("%q doesn't contain %q", got, want)
+		}
+	}
+}
+
+func TestReporterEnv(t *testing.T) {
+	useReporter(t, nil)
+	t.Setenv(assert.ReporterEnv, "json")
+
+	out := tpkg.CaptureOutput(t, func() {
+		failure(t, func(t testing.TB) {
+			assert.True(t, false)
+		})
+	})
+	if !strings.HasPrefix(out.Stdout, `{"test":`) || !strings.Contains(out.Stdout, `"assertion":"True"`) {
+		t.Errorf("expected JSON failure on the standard output, got %s", out.Stdout)
+	}
+
+	t.Setenv(assert.ReporterEnv, "text")
+	got := failure(t, func(t testing.TB) {
+		assert.True(t, false)
+	})
+	if got != "didn't get true" {
+		t.Errorf("expected text failure, got %s", got)
+	}
+}
+
+func TestFail(t *testing.T) {
+	var buf bytes.Buffer
+	useReporter(t, assert.JSONReporter{W: &buf})
+
+	failure(t, func(t testing.TB) {
+		assert.Fail(t, assert.Failure{Assertion: "Positive", Message: "not positive", Actual: "-1"}, "%d isn't positive", -1)
+	})
+	var f assert.Failure
+	if err := json.Unmarshal(buf.Bytes(), &f); err != nil {
+		t.Fatal(err)
+	}
+	if f.Assertion != "Positive" || f.Message != "-1 isn't positive" || f.Actual != "-1" || f.File != "report_test.go" {
+		t.Errorf("unexpected failure %+v", f)
+	}
+}
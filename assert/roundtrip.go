@@ -0,0 +1,114 @@
+package assert
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// RoundTrip encodes v with marshal, decodes the result into a new value of
+// type T with unmarshal, which receives a *T, and asserts the decoded value
+// is equal to v, as compared by Equal.
+//
+// The failure names the lossy fields: those set in v but zero after the
+// round trip, as with fields missing a tag or unexported.
+func RoundTrip[T any](t testing.TB, v T, marshal func(any) ([]byte, error), unmarshal func([]byte, any) error, out ...any) {
+	t.Helper()
+	roundTrip(t, "", v, marshal, unmarshal, out)
+}
+
+func JSONRoundTrip[T any](t testing.TB, v T, out ...any) {
+	t.Helper()
+	roundTrip(t, "JSON", v, json.Marshal, json.Unmarshal, out)
+}
+
+func GobRoundTrip[T any](t testing.TB, v T, out ...any) {
+	t.Helper()
+	roundTrip(t, "gob", v, func(v any) ([]byte, error) {
+		var b bytes.Buffer
+		err := gob.NewEncoder(&b).Encode(v)
+		return b.Bytes(), err
+	}, func(b []byte, v any) error {
+		return gob.NewDecoder(bytes.NewReader(b)).Decode(v)
+	}, out)
+}
+
+// TextRoundTrip round trips v through its MarshalText and UnmarshalText
+// methods.
+func TextRoundTrip[T encoding.TextMarshaler, PT interface {
+	*T
+	encoding.TextUnmarshaler
+}](t testing.TB, v T, out ...any) {
+	t.Helper()
+	roundTrip(t, "text", v, func(v any) ([]byte, error) {
+		return v.(T).MarshalText()
+	}, func(b []byte, v any) error {
+		return PT(v.(*T)).UnmarshalText(b)
+	}, out)
+}
+
+// BinaryRoundTrip round trips v through its MarshalBinary and
+// UnmarshalBinary methods.
+func BinaryRoundTrip[T encoding.BinaryMarshaler, PT interface {
+	*T
+	encoding.BinaryUnmarshaler
+}](t testing.TB, v T, out ...any) {
+	t.Helper()
+	roundTrip(t, "binary", v, func(v any) ([]byte, error) {
+		return v.(T).MarshalBinary()
+	}, func(b []byte, v any) error {
+		return PT(v.(*T)).UnmarshalBinary(b)
+	}, out)
+}
+
+func roundTrip[T any](t testing.TB, name string, v T, marshal func(any) ([]byte, error), unmarshal func([]byte, any) error, out []any) {
+	t.Helper()
+
+	if marshal == nil || unmarshal == nil {
+		panic("assert: nil marshal or unmarshal function")
+	}
+	trip := "round trip"
+	if name != "" {
+		trip = name + " round trip"
+	}
+	b, err := marshal(v)
+	if err != nil {
+		report(t, Failure{Message: fmt.Sprintf("%s: cannot encode %s: %v", trip, format(v), err)}, out)
+		return
+	}
+	var got T
+	if err := unmarshal(b, &got); err != nil {
+		report(t, Failure{Message: fmt.Sprintf("%s: cannot decode %q: %v", trip, b, err)}, out)
+		return
+	}
+
+	diffs := compare(got, v, nil)
+	if len(diffs) == 0 {
+		return
+	}
+	f := Failure{Expected: format(v), Actual: format(got), Diff: formatDiffs(diffs)}
+	f.Message = fmt.Sprintf("value changed through %s", trip)
+	if lost := lossyFields(diffs); len(lost) > 0 {
+		f.Message += fmt.Sprintf(", losing the fields %s", strings.Join(lost, ", "))
+	}
+	report(t, f, out)
+}
+
+// lossyFields returns the paths of the struct fields found zero in the
+// decoded value and set in the original one.
+func lossyFields(diffs []difference) []string {
+	var lost []string
+	for _, d := range diffs {
+		if d.path == "" || strings.HasSuffix(d.path, "]") || !d.a.IsValid() || !d.b.IsValid() {
+			continue
+		}
+		if d.a.IsZero() && !d.b.IsZero() {
+			lost = append(lost, d.path)
+		}
+	}
+	return lost
+}
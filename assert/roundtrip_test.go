@@ -0,0 +1,94 @@
+package assert_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xandalm/go-testing/assert"
+	"github.com/xandalm/go-testing/assert/asserttest"
+)
+
+type order struct {
+	ID      int       `json:"id"`
+	Placed  time.Time `json:"placed"`
+	Items   []string  `json:"items"`
+	Note    string    `json:"-"`
+	Address address   `json:"address"`
+	secret  string
+}
+
+type address struct {
+	Street string `json:"street"`
+	Zip    string `json:"-"`
+}
+
+type temperature float64
+
+func (c temperature) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%.1fC", float64(c))), nil
+}
+
+func (c *temperature) UnmarshalText(b []byte) error {
+	_, err := fmt.Sscanf(string(b), "%fC", (*float64)(c))
+	return err
+}
+
+func TestRoundTrip(t *testing.T) {
+	o := order{ID: 1, Placed: time.Now(), Items: []string{"book"}, Address: address{Street: "Main"}}
+
+	asserttest.ExpectSuccess(t, func(t testing.TB) {
+		assert.JSONRoundTrip(t, o)
+		assert.GobRoundTrip(t, o)
+		assert.TextRoundTrip(t, temperature(21.5))
+		assert.TextRoundTrip(t, netip.MustParseAddr("10.0.0.1"))
+		assert.BinaryRoundTrip(t, time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+		assert.RoundTrip(t, map[string]int{"a": 1}, json.Marshal, json.Unmarshal)
+	})
+
+	t.Run("lossy fields", func(t *testing.T) {
+		o := o
+		o.Note, o.secret, o.Address.Zip = "fragile", "x", "12345"
+		got := failure(t, func(t testing.TB) {
+			assert.JSONRoundTrip(t, o)
+		})
+		want := "value changed through JSON round trip, losing the fields .Note, .Address.Zip, .secret"
+		if !strings.HasPrefix(got, want+"\ndifferences:") {
+			t.Errorf("got %q, want prefix %q", got, want)
+		}
+		if !strings.Contains(got, `.Note: "" != "fragile"`) {
+			t.Errorf("differences should be listed, got %q", got)
+		}
+	})
+
+	t.Run("lossy text", func(t *testing.T) {
+		got := failure(t, func(t testing.TB) {
+			assert.TextRoundTrip(t, temperature(21.25))
+		})
+		if want := "value changed through text round trip\ndifferences:\n\tvalue: 21.2 != 21.25"; got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	})
+
+	t.Run("encoding errors", func(t *testing.T) {
+		got := failure(t, func(t testing.TB) {
+			assert.JSONRoundTrip(t, func() {})
+		})
+		if !strings.HasPrefix(got, "JSON round trip: cannot encode") {
+			t.Errorf("unexpected message %q", got)
+		}
+
+		got = failure(t, func(t testing.TB) {
+			assert.RoundTrip(t, 1, json.Marshal, func([]byte, any) error {
+				return errors.New("corrupt")
+			})
+		})
+		if want := `round trip: cannot decode "1": corrupt`; got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	})
+}
@@ -0,0 +1,106 @@
+package assert
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/xandalm/go-testing/internal/caller"
+)
+
+// Soft runs fn with a testing.TB that records every failure instead of
+// stopping. When fn returns, the recorded failures, with their file:line,
+// are reported together and t fails once.
+//
+// FailNow, and so Fatal, don't stop the goroutine within fn: the code after
+// a failed assertion still runs.
+func Soft(t testing.TB, fn func(s testing.TB)) {
+	t.Helper()
+
+	s := &softTB{TB: t, log: &failureLog{}}
+	fn(s)
+	if s.log.len() > 0 {
+		t.Fatal(s.log.report())
+	}
+}
+
+type softTB struct {
+	testing.TB
+	log *failureLog
+}
+
+func (s *softTB) Helper() {
+	s.log.helper(2)
+}
+
+func (s *softTB) Error(args ...any) {
+	s.log.add(fmt.Sprint(args...))
+}
+
+func (s *softTB) Errorf(format string, args ...any) {
+	s.log.add(fmt.Sprintf(format, args...))
+}
+
+func (s *softTB) Fatal(args ...any) {
+	s.log.add(fmt.Sprint(args...))
+}
+
+func (s *softTB) Fatalf(format string, args ...any) {
+	s.log.add(fmt.Sprintf(format, args...))
+}
+
+func (s *softTB) Fail() {
+	s.log.add("Fail called")
+}
+
+func (s *softTB) FailNow() {
+	s.log.add("FailNow called")
+}
+
+func (s *softTB) Failed() bool {
+	return s.log.len() > 0 || s.TB.Failed()
+}
+
+var pkgPrefix = caller.Package(isNil)
+
+// failureLog collects failure messages along with the location of the
+// first caller outside this package which isn't marked as helper.
+type failureLog struct {
+	mu       sync.Mutex
+	helpers  caller.Helpers
+	failures []string
+}
+
+func (l *failureLog) helper(skip int) {
+	l.helpers.Mark(skip)
+}
+
+func (l *failureLog) location() string {
+	frame, ok := l.helpers.Frame(2, pkgPrefix)
+	if !ok {
+		return "???:1"
+	}
+	return fmt.Sprintf("%s:%d", filepath.Base(frame.File), frame.Line)
+}
+
+func (l *failureLog) add(msg string) {
+	loc := l.location()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.failures = append(l.failures, loc+": "+msg)
+}
+
+func (l *failureLog) len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.failures)
+}
+
+func (l *failureLog) report() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return fmt.Sprintf("%d assertion(s) failed:\n\t%s", len(l.failures), strings.Join(l.failures, "\n\t"))
+}
@@ -0,0 +1,72 @@
+package assert_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/xandalm/go-testing/assert"
+)
+
+func assertPositive(t testing.TB, v int) {
+	t.Helper()
+	assert.Greater(t, v, 0)
+}
+
+func TestSoft(t *testing.T) {
+	t.Run("reports every failure once at the end", func(t *testing.T) {
+		tt := &recordingTester{T: t}
+		reached := false
+		assert.Soft(tt, func(s testing.TB) {
+			assert.Equal(s, 1, 2)
+			assertPositive(s, -1)
+			s.Errorf("custom %s", "failure")
+			assert.True(s, true)
+			reached = true
+		})
+
+		if !reached {
+			t.Fatal("failures shouldn't stop the scope")
+		}
+		if len(tt.fatals) != 1 {
+			t.Fatalf("expected one fatal report, got %v", tt.fatals)
+		}
+		pattern := `^3 assertion\(s\) failed:
+	soft_test\.go:\d+: expected equal values, but got 1 and 2
+	soft_test\.go:\d+: -1 is actually smaller than 0
+	soft_test\.go:\d+: custom failure$`
+		if !regexp.MustCompile(pattern).MatchString(tt.fatals[0]) {
+			t.Errorf("unexpected report:\n%s", tt.fatals[0])
+		}
+	})
+	t.Run("doesn't fail when every assertion passes", func(t *testing.T) {
+		tt := &recordingTester{T: t}
+		assert.Soft(tt, func(s testing.TB) {
+			assert.Equal(s, 1, 1)
+			assert.NotNil(s, errFoo)
+			if s.Failed() {
+				t.Error("scope shouldn't be failed")
+			}
+		})
+		if len(tt.fatals) != 0 {
+			t.Errorf("expected no report, got %v", tt.fatals)
+		}
+	})
+	t.Run("Failed reports recorded failures", func(t *testing.T) {
+		tt := &recordingTester{T: t}
+		assert.Soft(tt, func(s testing.TB) {
+			assert.Nil(s, errFoo)
+			if !s.Failed() {
+				t.Error("scope should be failed")
+			}
+		})
+	})
+	t.Run("reports Fail", func(t *testing.T) {
+		tt := &recordingTester{T: t}
+		assert.Soft(tt, func(s testing.TB) {
+			s.Fail()
+		})
+		if len(tt.fatals) != 1 || !regexp.MustCompile(`^1 assertion\(s\) failed:\n\tsoft_test\.go:\d+: Fail called$`).MatchString(tt.fatals[0]) {
+			t.Errorf("expected Fail to be reported, got %q", tt.fatals)
+		}
+	})
+}
@@ -0,0 +1,126 @@
+package assert
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"strings"
+	"sync"
+)
+
+type sourceFile struct {
+	fset *token.FileSet
+	file *ast.File
+	src  []byte
+}
+
+var sources = struct {
+	sync.Mutex
+	files map[string]*sourceFile
+}{files: map[string]*sourceFile{}}
+
+// parseSource parses the file once, caching the result. It returns nil if
+// the file can't be read or parsed.
+func parseSource(name string) *sourceFile {
+	sources.Lock()
+	defer sources.Unlock()
+
+	if f, ok := sources.files[name]; ok {
+		return f
+	}
+	var f *sourceFile
+	if src, err := os.ReadFile(name); err == nil {
+		fset := token.NewFileSet()
+		if file, err := parser.ParseFile(fset, name, src, 0); err == nil {
+			f = &sourceFile{fset, file, src}
+		}
+	}
+	sources.files[name] = f
+	return f
+}
+
+// argSource returns the source text of the i-th value argument (the
+// testing.TB doesn't count) given to the assertion in the call made by the
+// first caller outside this package. It returns "" when the source isn't
+// available or the assertion was called by one of the subpackages.
+func argSource(i int) string {
+	entry, frame := callSite()
+	if !strings.HasPrefix(entry, pkgPrefix) {
+		return ""
+	}
+	return callArgSource(frame.File, frame.Line, entry, i)
+}
+
+func callArgSource(file string, line int, entry string, i int) string {
+	f := parseSource(file)
+	if f == nil {
+		return ""
+	}
+
+	name := assertionName(entry)
+	if !strings.HasPrefix(entry, pkgPrefix+"(*Assert).") {
+		// package functions take the testing.TB first
+		i++
+	}
+
+	var arg ast.Expr
+	ast.Inspect(f.file, func(n ast.Node) bool {
+		if arg != nil {
+			return false
+		}
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		start, end := f.fset.Position(call.Pos()).Line, f.fset.Position(call.End()).Line
+		if line < start || line > end {
+			return false
+		}
+		if calleeName(call.Fun) == name && i < len(call.Args) {
+			arg = call.Args[i]
+			return false
+		}
+		return true
+	})
+	if arg == nil || isLiteral(arg) {
+		return ""
+	}
+	return string(f.src[f.fset.Position(arg.Pos()).Offset:f.fset.Position(arg.End()).Offset])
+}
+
+// isLiteral reports whether quoting e would just repeat its value.
+func isLiteral(e ast.Expr) bool {
+	switch e := e.(type) {
+	case *ast.BasicLit:
+		return true
+	case *ast.Ident:
+		return e.Name == "true" || e.Name == "false" || e.Name == "nil"
+	default:
+		return false
+	}
+}
+
+func calleeName(fun ast.Expr) string {
+	switch fun := fun.(type) {
+	case *ast.Ident:
+		return fun.Name
+	case *ast.SelectorExpr:
+		return fun.Sel.Name
+	case *ast.IndexExpr:
+		return calleeName(fun.X)
+	case *ast.IndexListExpr:
+		return calleeName(fun.X)
+	default:
+		return ""
+	}
+}
+
+// quoted returns the source of the i-th value argument of the assertion
+// preceded by prep, e.g. " from `ok`", or "" if the source isn't available.
+func quoted(i int, prep string) string {
+	if src := argSource(i); src != "" {
+		return " " + prep + " `" + src + "`"
+	}
+	return ""
+}
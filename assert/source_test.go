@@ -0,0 +1,86 @@
+package assert_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/xandalm/go-testing/assert"
+	"github.com/xandalm/go-testing/assert/asserttest"
+)
+
+type user struct {
+	Active bool
+	Roles  []string
+}
+
+func failure(t *testing.T, fn func(t testing.TB)) string {
+	t.Helper()
+	r := asserttest.ExpectFailure(t, fn)
+	msgs := r.Messages("Fatal")
+	if len(msgs) != 1 {
+		t.Fatalf("expected one failure, got %v", msgs)
+	}
+	return msgs[0]
+}
+
+func TestSourceExpressions(t *testing.T) {
+	u := user{Active: true}
+	var err error = errFoo
+	cases := []struct {
+		name string
+		fn   func(t testing.TB)
+		want string
+	}{
+		{
+			"True",
+			func(t testing.TB) { assert.True(t, u.Active && len(u.Roles) > 0) },
+			"didn't get true from `u.Active && len(u.Roles) > 0`",
+		},
+		{
+			"False",
+			func(t testing.TB) { assert.False(t, u.Active) },
+			"didn't get false from `u.Active`",
+		},
+		{
+			"Nil",
+			func(t testing.TB) { assert.Nil(t, err) },
+			"expected nil value for `err`, got error",
+		},
+		{
+			"NotNil",
+			func(t testing.TB) { assert.NotNil(t, u.Roles) },
+			"expected not nil value for `u.Roles`",
+		},
+		{
+			"Empty",
+			func(t testing.TB) { assert.Empty(t, fmt.Sprint(u.Active)) },
+			"expected empty for `fmt.Sprint(u.Active)`, but got \"true\"",
+		},
+		{
+			"multi-line call",
+			func(t testing.TB) {
+				assert.True(t,
+					len(u.Roles) == 1,
+				)
+			},
+			"didn't get true from `len(u.Roles) == 1`",
+		},
+		{
+			"method",
+			func(t testing.TB) { assert.New(t).True(len(u.Roles) > 0) },
+			"didn't get true from `len(u.Roles) > 0`",
+		},
+		{
+			"literal isn't quoted",
+			func(t testing.TB) { assert.True(t, false) },
+			"didn't get true",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := failure(t, c.fn); got != c.want {
+				t.Errorf("got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package testing
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/xandalm/go-testing/assert/logtest"
+)
+
+// Output is what was written to the standard output and error.
+type Output struct {
+	Stdout string
+	Stderr string
+}
+
+// CaptureOutput calls fn and returns what it wrote to os.Stdout and
+// os.Stderr, which are restored when fn returns, panics or ends the test.
+//
+// The standard files are process wide, so tests capturing output must not
+// run in parallel.
+func CaptureOutput(t testing.TB, fn func()) Output {
+	t.Helper()
+
+	var out Output
+	restoreOut, waitOut := capture(t, &os.Stdout, &out.Stdout)
+	defer restoreOut()
+	restoreErr, waitErr := capture(t, &os.Stderr, &out.Stderr)
+	defer restoreErr()
+
+	fn()
+	restoreOut()
+	restoreErr()
+	waitOut()
+	waitErr()
+	return out
+}
+
+// capture replaces *f with a pipe copied into dst. It returns a function
+// restoring *f, safe to call more than once, and a function waiting for the
+// copy to finish once *f is restored.
+func capture(t testing.TB, f **os.File, dst *string) (restore, wait func()) {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("testing: cannot capture output, %v", err)
+	}
+	orig := *f
+	*f = w
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		var b bytes.Buffer
+		io.Copy(&b, r)
+		r.Close()
+		*dst = b.String()
+	}()
+	var once sync.Once
+	restore = func() {
+		once.Do(func() {
+			*f = orig
+			w.Close()
+		})
+	}
+	return restore, func() { <-done }
+}
+
+// CaptureLog calls fn and returns the records it logged through the
+// default loggers of the log and log/slog packages, which are restored
+// when fn returns, panics or ends the test. The messages of the log
+// package are recorded at the info level.
+//
+// The default loggers are process wide, so tests capturing logs must not
+// run in parallel.
+func CaptureLog(t testing.TB, fn func()) []logtest.Record {
+	t.Helper()
+
+	h := logtest.NewHandler()
+	defer logtest.SetDefault(h)()
+
+	fn()
+	return h.Records()
+}
@@ -0,0 +1,68 @@
+package testing_test
+
+import (
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"testing"
+
+	tpkg "github.com/xandalm/go-testing"
+	"github.com/xandalm/go-testing/assert"
+)
+
+func TestCaptureOutput(t *testing.T) {
+	stdout, stderr := os.Stdout, os.Stderr
+
+	out := tpkg.CaptureOutput(t, func() {
+		fmt.Println("hello")
+		fmt.Fprintln(os.Stderr, "oops")
+		fmt.Print("bye")
+	})
+
+	assert.Equal(t, out, tpkg.Output{Stdout: "hello\nbye", Stderr: "oops\n"})
+	assert.ContainsLines(t, out.Stdout, []string{"hello", "bye"})
+	if os.Stdout != stdout || os.Stderr != stderr {
+		t.Error("standard files should be restored")
+	}
+
+	t.Run("restores on panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			tpkg.CaptureOutput(t, func() {
+				fmt.Println("before panic")
+				panic("boom")
+			})
+		})
+		if os.Stdout != stdout || os.Stderr != stderr {
+			t.Error("standard files should be restored")
+		}
+	})
+}
+
+func TestCaptureLog(t *testing.T) {
+	defaultLogger, writer, flags := slog.Default(), log.Writer(), log.Flags()
+
+	records := tpkg.CaptureLog(t, func() {
+		log.Printf("listening on %s", ":5000")
+		slog.Warn("slow request", "path", "/users", slog.Group("req", "ms", 1500))
+		slog.With("id", 7).WithGroup("user").Error("not found", "name", "bob")
+	})
+
+	if len(records) != 3 {
+		t.Fatalf("expected 3 records, got %v", records)
+	}
+	got := make([]string, len(records))
+	for i, r := range records {
+		got[i] = r.String()
+	}
+	assert.Equal(t, got, []string{
+		`INFO "listening on :5000"`,
+		`WARN "slow request" path=/users req.ms=1500`,
+		`ERROR "not found" id=7 user.name=bob`,
+	})
+	assert.Equal(t, records[2].Attrs, map[string]any{"id": int64(7), "user.name": "bob"})
+
+	if slog.Default() != defaultLogger || log.Writer() != writer || log.Flags() != flags {
+		t.Error("default loggers should be restored")
+	}
+}
@@ -0,0 +1,152 @@
+// Package cases runs table-driven tests: each case is run as a subtest
+// calling the function under test with its input and checking the output
+// and error.
+package cases
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/xandalm/go-testing/assert"
+)
+
+// Case is an input of the function under test with its expected output or
+// error. Cases loaded from JSON files use the field names in lower case,
+// with the expected error text as "err".
+type Case[In, Out any] struct {
+	Name string `json:"name"`
+	In   In     `json:"in"`
+	Want Out    `json:"want"`
+	// WantErr is the error expected, as matched by errors.Is.
+	WantErr error `json:"-"`
+	// WantErrText is text expected in the message of the error.
+	WantErrText string `json:"err"`
+	// Only makes the cases so marked the only ones run.
+	Only bool `json:"only"`
+	Skip bool `json:"skip"`
+}
+
+func (c Case[In, Out]) wantsErr() bool {
+	return c.WantErr != nil || c.WantErrText != ""
+}
+
+// Option configures Run.
+type Option func(*config)
+
+type config struct {
+	parallel bool
+	equal    []assert.Option
+}
+
+// Parallel runs the cases in parallel with each other.
+func Parallel() Option {
+	return func(c *config) {
+		c.parallel = true
+	}
+}
+
+// EqualOptions compares the outputs as assert.EqualOpts with opts, rather
+// than as assert.Equal.
+func EqualOptions(opts ...assert.Option) Option {
+	return func(c *config) {
+		c.equal = append(c.equal, opts...)
+	}
+}
+
+// Run runs each case as a subtest named after it, calling fn with its
+// input. Once all cases are done, the names of the failed ones are logged.
+func Run[In, Out any](t *testing.T, cases []Case[In, Out], fn func(In) (Out, error), opts ...Option) {
+	t.Helper()
+
+	if fn == nil {
+		panic("cases: nil function")
+	}
+	var cfg config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	only := slices.ContainsFunc(cases, func(c Case[In, Out]) bool { return c.Only })
+
+	var mu sync.Mutex
+	failed := make([]string, len(cases))
+	t.Cleanup(func() {
+		names := slices.DeleteFunc(failed, func(name string) bool { return name == "" })
+		if len(names) > 0 {
+			t.Logf("failed cases (%d of %d): %s", len(names), len(cases), strings.Join(names, ", "))
+		}
+	})
+
+	for i, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			t.Cleanup(func() {
+				if t.Failed() {
+					mu.Lock()
+					defer mu.Unlock()
+					failed[i] = c.Name
+				}
+			})
+			switch {
+			case c.Skip:
+				t.Skip("case marked Skip")
+			case only && !c.Only:
+				t.Skip("other cases marked Only")
+			}
+			if cfg.parallel {
+				t.Parallel()
+			}
+			check(t, c, fn, cfg)
+		})
+	}
+}
+
+func check[In, Out any](t *testing.T, c Case[In, Out], fn func(In) (Out, error), cfg config) {
+	got, err := fn(c.In)
+	switch {
+	case c.wantsErr() && err == nil:
+		assert.Fail(t, assert.Failure{Message: fmt.Sprintf("expected error %s, but got output %s", wantedErr(c), assert.Format(got))})
+	case c.WantErr != nil && !errors.Is(err, c.WantErr):
+		assert.Fail(t, assert.Failure{Message: fmt.Sprintf("expected error %v, but got %v", c.WantErr, err)})
+	case c.WantErrText != "" && !strings.Contains(err.Error(), c.WantErrText):
+		assert.Fail(t, assert.Failure{Message: fmt.Sprintf("expected error containing %q, but got %v", c.WantErrText, err)})
+	case c.wantsErr():
+	case err != nil:
+		assert.Fail(t, assert.Failure{Message: fmt.Sprintf("unexpected error %v", err)})
+	case cfg.equal != nil:
+		assert.EqualOpts(t, got, c.Want, cfg.equal...)
+	default:
+		assert.Equal(t, got, c.Want)
+	}
+}
+
+func wantedErr[In, Out any](c Case[In, Out]) string {
+	if c.WantErr != nil {
+		return c.WantErr.Error()
+	}
+	return fmt.Sprintf("containing %q", c.WantErrText)
+}
+
+// Load reads the cases from the JSON file, such as one in testdata, holding
+// an array of cases. Unknown fields are rejected, to catch misspelled
+// ones.
+func Load[In, Out any](t testing.TB, file string) []Case[In, Out] {
+	t.Helper()
+
+	b, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("cases: cannot load cases, %v", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	var cases []Case[In, Out]
+	if err := dec.Decode(&cases); err != nil {
+		t.Fatalf("cases: cannot load cases from %s, %v", file, err)
+	}
+	return cases
+}
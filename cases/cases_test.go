@@ -0,0 +1,91 @@
+package cases_test
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/xandalm/go-testing/cases"
+)
+
+var errOdd = errors.New("odd")
+
+func half(n int) (int, error) {
+	if n%2 != 0 {
+		return 0, errOdd
+	}
+	return n / 2, nil
+}
+
+func TestRun(t *testing.T) {
+	cases.Run(t, []cases.Case[int, int]{
+		{Name: "zero", In: 0, Want: 0},
+		{Name: "even", In: 8, Want: 4},
+		{Name: "odd", In: 3, WantErr: errOdd},
+		{Name: "skipped", In: 1, Want: 1, Skip: true},
+	}, half, cases.Parallel())
+}
+
+func TestRunOnly(t *testing.T) {
+	var ran []int
+	cases.Run(t, []cases.Case[int, int]{
+		{Name: "first", In: 1, Want: 1},
+		{Name: "second", In: 2, Want: 2, Only: true},
+		{Name: "third", In: 3, Want: 3},
+	}, func(n int) (int, error) {
+		ran = append(ran, n)
+		return n, nil
+	})
+	if len(ran) != 1 || ran[0] != 2 {
+		t.Errorf("expected only the second case to run, got %v", ran)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	cs := cases.Load[string, int](t, "testdata/atoi.json")
+	if len(cs) != 3 {
+		t.Fatalf("expected 3 cases, got %d", len(cs))
+	}
+	cases.Run(t, cs, strconv.Atoi)
+}
+
+// failingEnv makes TestFailingCases run, from TestRunFailures.
+const failingEnv = "CASES_FAILING"
+
+func TestFailingCases(t *testing.T) {
+	if os.Getenv(failingEnv) == "" {
+		t.Skip("run by TestRunFailures")
+	}
+	cases.Run(t, []cases.Case[int, int]{
+		{Name: "ok", In: 2, Want: 1},
+		{Name: "wrong output", In: 4, Want: 3},
+		{Name: "missing error", In: 2, WantErr: errOdd},
+		{Name: "unexpected error", In: 5, Want: 2},
+		{Name: "error text/wrong", In: 7, WantErrText: "even"},
+	}, half, cases.Parallel())
+}
+
+func TestRunFailures(t *testing.T) {
+	cmd := exec.Command(os.Args[0], "-test.run=^TestFailingCases$", "-test.v")
+	cmd.Env = append(os.Environ(), failingEnv+"=1")
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected failing cases to fail, output:\n%s", out)
+	}
+	got := string(out)
+	for _, want := range []string{
+		"--- PASS: TestFailingCases/ok",
+		"--- FAIL: TestFailingCases/wrong_output",
+		"expected error odd, but got output 1",
+		"unexpected error odd",
+		`expected error containing "even", but got odd`,
+		"failed cases (4 of 5): wrong output, missing error, unexpected error, error text/wrong\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output should contain %q, got:\n%s", want, got)
+		}
+	}
+}
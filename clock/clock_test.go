@@ -0,0 +1,32 @@
+package clock_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/xandalm/go-testing/assert"
+	"github.com/xandalm/go-testing/clock"
+)
+
+func TestNew(t *testing.T) {
+	c := clock.New()
+	start := c.Now()
+
+	<-c.After(time.Millisecond)
+	<-c.NewTimer(time.Millisecond).C()
+	c.Sleep(time.Millisecond)
+	assert.True(t, c.Since(start) >= 3*time.Millisecond)
+
+	ticker := c.NewTicker(time.Millisecond)
+	<-ticker.C()
+	ticker.Stop()
+
+	done := make(chan struct{})
+	c.AfterFunc(time.Millisecond, func() { close(done) })
+	<-done
+
+	timer := c.NewTimer(time.Hour)
+	assert.True(t, timer.Stop())
+	assert.False(t, timer.Reset(time.Hour))
+	assert.True(t, timer.Stop())
+}
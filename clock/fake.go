@@ -0,0 +1,237 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// FakeClock is a Clock whose time only moves when set by Advance or Set,
+// which fire the timers and tickers due in order of their times. Timers
+// due at the same time fire in the order they were set.
+//
+// The functions of AfterFunc run in the goroutine moving the clock, before
+// it moves further, or in their own goroutine if due when set, as with the
+// time package.
+type FakeClock struct {
+	mu      sync.Mutex
+	changed *sync.Cond
+	now     time.Time
+	waiters []*waiter
+	seq     int
+}
+
+// NewFake returns a fake clock set to now.
+func NewFake(now time.Time) *FakeClock {
+	c := &FakeClock{now: now}
+	c.changed = sync.NewCond(&c.mu)
+	return c
+}
+
+// waiter is a timer, a ticker or a sleep waiting for the clock.
+type waiter struct {
+	clock  *FakeClock
+	when   time.Time
+	seq    int
+	period time.Duration
+	c      chan time.Time
+	fn     func()
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *FakeClock) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	return c.NewTimer(d).C()
+}
+
+func (c *FakeClock) Sleep(d time.Duration) {
+	<-c.After(d)
+}
+
+func (c *FakeClock) NewTimer(d time.Duration) Timer {
+	w := &waiter{clock: c, c: make(chan time.Time, 1)}
+	w.Reset(d)
+	return w
+}
+
+func (c *FakeClock) AfterFunc(d time.Duration, f func()) Timer {
+	if f == nil {
+		panic("clock: nil func")
+	}
+	w := &waiter{clock: c, fn: f}
+	w.Reset(d)
+	return w
+}
+
+func (c *FakeClock) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+	t := &ticker{clock: c, c: make(chan time.Time, 1)}
+	t.Reset(d)
+	return t
+}
+
+// Advance moves the clock forward by d.
+func (c *FakeClock) Advance(d time.Duration) {
+	if d < 0 {
+		panic("clock: negative duration to advance")
+	}
+	c.mu.Lock()
+	now := c.now
+	c.mu.Unlock()
+	c.Set(now.Add(d))
+}
+
+// Set sets the clock to t, firing the timers and tickers due by then. The
+// clock moves to the time of each of them in turn, so Now is their time
+// while they fire.
+func (c *FakeClock) Set(t time.Time) {
+	for {
+		c.mu.Lock()
+		w := c.next(t)
+		if w == nil {
+			c.now = t
+			c.mu.Unlock()
+			return
+		}
+		if w.when.After(c.now) {
+			c.now = w.when
+		}
+		fn := w.fire()
+		c.mu.Unlock()
+
+		if fn != nil {
+			fn()
+		}
+	}
+}
+
+// next returns the first waiter due by t.
+func (c *FakeClock) next(t time.Time) *waiter {
+	var next *waiter
+	for _, w := range c.waiters {
+		if w.when.After(t) {
+			continue
+		}
+		if next == nil || w.when.Before(next.when) || w.when.Equal(next.when) && w.seq < next.seq {
+			next = w
+		}
+	}
+	return next
+}
+
+// BlockUntil blocks until n timers, tickers and sleeps, at least, wait for
+// the clock, so the goroutines of the code under test got to wait before
+// the clock is advanced.
+func (c *FakeClock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.waiters) < n {
+		c.changed.Wait()
+	}
+}
+
+// Waiters returns the number of timers, tickers and sleeps waiting for the
+// clock.
+func (c *FakeClock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
+
+// fire fires the waiter, rescheduling tickers and removing the others. It
+// returns the function to call of AfterFunc timers.
+func (w *waiter) fire() func() {
+	c := w.clock
+	if w.period > 0 {
+		w.when = w.when.Add(w.period)
+	} else {
+		w.remove()
+	}
+	if w.fn != nil {
+		return w.fn
+	}
+	select {
+	case w.c <- c.now:
+	default:
+		// like the time package, ticks are dropped for slow receivers
+	}
+	return nil
+}
+
+// remove removes the waiter from its clock, reporting whether it was
+// waiting. The clock must be locked.
+func (w *waiter) remove() bool {
+	c := w.clock
+	for i, o := range c.waiters {
+		if o == w {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			c.changed.Broadcast()
+			return true
+		}
+	}
+	return false
+}
+
+// schedule makes the waiter wait for d, firing at once if it's due.
+func (w *waiter) schedule(d time.Duration) bool {
+	c := w.clock
+	active := w.remove()
+	c.seq++
+	w.when, w.seq = c.now.Add(d), c.seq
+	if d <= 0 {
+		if fn := w.fire(); fn != nil {
+			go fn()
+		}
+		return active
+	}
+	c.waiters = append(c.waiters, w)
+	c.changed.Broadcast()
+	return active
+}
+
+func (w *waiter) C() <-chan time.Time {
+	return w.c
+}
+
+func (w *waiter) Stop() bool {
+	w.clock.mu.Lock()
+	defer w.clock.mu.Unlock()
+	return w.remove()
+}
+
+func (w *waiter) Reset(d time.Duration) bool {
+	w.clock.mu.Lock()
+	defer w.clock.mu.Unlock()
+	return w.schedule(d)
+}
+
+// ticker is a waiter firing periodically.
+type ticker waiter
+
+func (t *ticker) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *ticker) Stop() {
+	(*waiter)(t).Stop()
+}
+
+func (t *ticker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("clock: non-positive interval for Ticker.Reset")
+	}
+	w := (*waiter)(t)
+	w.clock.mu.Lock()
+	defer w.clock.mu.Unlock()
+	w.period = d
+	w.schedule(d)
+}
@@ -0,0 +1,141 @@
+package clock_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xandalm/go-testing/assert"
+	"github.com/xandalm/go-testing/clock"
+)
+
+var epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func received(ch <-chan time.Time) (time.Time, bool) {
+	select {
+	case t := <-ch:
+		return t, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+func TestFakeClock(t *testing.T) {
+	t.Run("timers", func(t *testing.T) {
+		c := clock.NewFake(epoch)
+		after := c.After(2 * time.Second)
+		timer := c.NewTimer(time.Second)
+		assert.Equal(t, c.Waiters(), 2)
+
+		c.Advance(time.Second - 1)
+		_, ok := received(timer.C())
+		assert.False(t, ok)
+
+		c.Advance(1)
+		got, ok := received(timer.C())
+		assert.True(t, ok)
+		assert.Equal(t, got, epoch.Add(time.Second))
+		assert.False(t, timer.Stop())
+
+		c.Advance(time.Hour)
+		got, _ = received(after)
+		assert.Equal(t, got, epoch.Add(2*time.Second))
+		assert.Equal(t, c.Now(), epoch.Add(time.Hour+time.Second))
+		assert.Equal(t, c.Since(epoch), time.Hour+time.Second)
+		assert.Equal(t, c.Waiters(), 0)
+	})
+
+	t.Run("stop and reset", func(t *testing.T) {
+		c := clock.NewFake(epoch)
+		timer := c.NewTimer(time.Second)
+		assert.True(t, timer.Stop())
+		c.Advance(time.Minute)
+		_, ok := received(timer.C())
+		assert.False(t, ok)
+
+		assert.False(t, timer.Reset(time.Second))
+		c.Advance(time.Second)
+		got, ok := received(timer.C())
+		assert.True(t, ok)
+		assert.Equal(t, got, epoch.Add(time.Minute+time.Second))
+
+		_, ok = received(c.After(0))
+		assert.True(t, ok)
+	})
+
+	t.Run("tickers", func(t *testing.T) {
+		c := clock.NewFake(epoch)
+		ticker := c.NewTicker(time.Second)
+		var ticks []time.Duration
+		for range 3 {
+			c.Advance(time.Second)
+			tick, _ := received(ticker.C())
+			ticks = append(ticks, tick.Sub(epoch))
+		}
+		assert.Equal(t, ticks, []time.Duration{time.Second, 2 * time.Second, 3 * time.Second})
+
+		c.Advance(5 * time.Second)
+		tick, _ := received(ticker.C())
+		_, more := received(ticker.C())
+		assert.Equal(t, tick, epoch.Add(4*time.Second))
+		assert.False(t, more)
+
+		ticker.Reset(time.Minute)
+		c.Advance(time.Second)
+		_, ok := received(ticker.C())
+		assert.False(t, ok)
+		ticker.Stop()
+		assert.Equal(t, c.Waiters(), 0)
+	})
+
+	t.Run("fires in order", func(t *testing.T) {
+		c := clock.NewFake(epoch)
+		var order []string
+		at := func(name string) func() {
+			return func() { order = append(order, name+"@"+c.Since(epoch).String()) }
+		}
+		c.AfterFunc(3*time.Second, at("c"))
+		c.AfterFunc(time.Second, func() {
+			at("a")()
+			c.AfterFunc(time.Second, at("b"))
+		})
+		c.AfterFunc(3*time.Second, at("d"))
+		c.Set(epoch.Add(time.Minute))
+		assert.Equal(t, order, []string{"a@1s", "b@2s", "c@3s", "d@3s"})
+	})
+
+	t.Run("due func", func(t *testing.T) {
+		c := clock.NewFake(epoch)
+		done := make(chan time.Time)
+		c.AfterFunc(0, func() { done <- c.Now() })
+		assert.Equal(t, <-done, epoch)
+		assert.Equal(t, c.Waiters(), 0)
+	})
+
+	t.Run("sleep and block", func(t *testing.T) {
+		c := clock.NewFake(epoch)
+		var wg sync.WaitGroup
+		woke := make([]time.Time, 3)
+		for i := range woke {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				c.Sleep(time.Duration(i+1) * time.Second)
+				woke[i] = c.Now()
+			}()
+		}
+		c.BlockUntil(3)
+		c.Advance(3 * time.Second)
+		wg.Wait()
+		for _, w := range woke {
+			assert.True(t, !w.Before(epoch.Add(time.Second)))
+		}
+	})
+
+	t.Run("misuse", func(t *testing.T) {
+		c := clock.NewFake(epoch)
+		assert.PanicIs(t, func() { c.Advance(-1) }, "clock: negative duration to advance")
+		assert.PanicIs(t, func() { c.NewTicker(0) }, "clock: non-positive interval for NewTicker")
+		assert.PanicIs(t, func() { c.AfterFunc(1, nil) }, "clock: nil func")
+	})
+}
@@ -0,0 +1,411 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+const mockPath = "github.com/xandalm/go-testing/assert/mock"
+
+// options are the options of a generated mock.
+type options struct {
+	// name is the name of the mock type, "Mock" and the interface name by
+	// default.
+	name string
+	// pkg is the package of the generated file, the package of the
+	// interface by default.
+	pkg string
+}
+
+// embedded are the names of the embedded mock.Expectations and its
+// methods, which the methods of mocks can't be named after.
+var embedded = map[string]bool{
+	"Expectations": true,
+	"Call":         true,
+	"Calls":        true,
+	"Expect":       true,
+	"Verify":       true,
+}
+
+// generate returns the source of a mock of the interface named iface
+// declared in the package in dir, read from source.
+func generate(dir, iface string, opts options) ([]byte, error) {
+	pkg, err := load(dir)
+	if err != nil {
+		return nil, err
+	}
+	obj := pkg.Scope().Lookup(iface)
+	if obj == nil {
+		return nil, fmt.Errorf("%s not found in package %s", iface, pkg.Name())
+	}
+	named, ok := obj.Type().(*types.Named)
+	if !ok {
+		return nil, fmt.Errorf("%s isn't a named type", iface)
+	}
+	if named.TypeParams().Len() > 0 {
+		return nil, fmt.Errorf("%s is generic, which isn't supported", iface)
+	}
+	it, ok := named.Underlying().(*types.Interface)
+	if !ok {
+		return nil, fmt.Errorf("%s isn't an interface", iface)
+	}
+
+	m := mockFile{Interface: iface, Name: opts.name, Package: opts.pkg}
+	if m.Name == "" {
+		m.Name = "Mock" + iface
+	}
+	if m.Package == "" {
+		m.Package = pkg.Name()
+	}
+	// The interface's package is imported if the mock is generated in
+	// another package.
+	external := m.Package != pkg.Name()
+	srcPath := pkg.Path()
+	if external {
+		if srcPath, err = importPath(dir); err != nil {
+			return nil, err
+		}
+	}
+
+	// The types are printed twice: first to find the packages they use,
+	// and then with the names given to the packages.
+	imports := newImports()
+	imports.add(mockPath, "mock")
+	qualifier := func(p *types.Package) string {
+		if p != pkg {
+			return imports.add(p.Path(), p.Name())
+		}
+		if external {
+			return imports.add(srcPath, p.Name())
+		}
+		return ""
+	}
+	names := map[string]bool{}
+	for i := range it.NumMethods() {
+		names[it.Method(i).Name()] = true
+	}
+	var methods []*types.Func
+	for i := range it.NumMethods() {
+		fn := it.Method(i)
+		if !fn.Exported() && external {
+			return nil, fmt.Errorf("%s has the unexported method %s, which can't be implemented outside its package", iface, fn.Name())
+		}
+		if embedded[fn.Name()] {
+			return nil, fmt.Errorf("%s has the method %s, which collides with the mock.Expectations embedded in the mock", iface, fn.Name())
+		}
+		if expect := "Expect" + title(fn.Name()); names[expect] {
+			return nil, fmt.Errorf("%s has the method %s, which collides with the method of the mock expecting calls of %s", iface, expect, fn.Name())
+		}
+		methods = append(methods, fn)
+		types.TypeString(fn.Type(), qualifier)
+	}
+	if !external {
+		imports.reserve(pkg.Scope().Names()...)
+	}
+	imports.name()
+	m.Imports = imports.specs()
+	m.Mock = imports.names[mockPath]
+	for _, fn := range methods {
+		m.Methods = append(m.Methods, newMethod(fn, qualifier, imports))
+	}
+
+	var b bytes.Buffer
+	if err := mockTemplate.Execute(&b, m); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("invalid generated code, %v", err)
+	}
+	return src, nil
+}
+
+// load parses and type checks the package in dir, skipping its tests and
+// the files excluded by build constraints.
+func load(dir string) (*types.Package, error) {
+	bp, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, name := range slices.Concat(bp.GoFiles, bp.CgoFiles) {
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	return conf.Check(bp.ImportPath, fset, files, nil)
+}
+
+// importPath returns the import path of the package in dir, found from
+// the go.mod file of its module.
+func importPath(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for d := abs; ; d = filepath.Dir(d) {
+		f, err := os.Open(filepath.Join(d, "go.mod"))
+		if errors.Is(err, os.ErrNotExist) {
+			if filepath.Dir(d) == d {
+				return "", fmt.Errorf("no go.mod found for %s", dir)
+			}
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		defer f.Close()
+		s := bufio.NewScanner(f)
+		for s.Scan() {
+			if mod, ok := strings.CutPrefix(strings.TrimSpace(s.Text()), "module "); ok {
+				rel, err := filepath.Rel(d, abs)
+				if err != nil {
+					return "", err
+				}
+				return path.Join(strings.Trim(mod, "\" "), filepath.ToSlash(rel)), nil
+			}
+		}
+		return "", fmt.Errorf("no module path in %s", f.Name())
+	}
+}
+
+// imports names the packages imported by the generated file, giving
+// aliases to those whose names are taken.
+type imports struct {
+	paths    []string
+	pkgNames map[string]string
+	names    map[string]string
+	reserved map[string]bool
+}
+
+func newImports() *imports {
+	return &imports{pkgNames: map[string]string{}, names: map[string]string{}, reserved: map[string]bool{}}
+}
+
+// add adds the package with the given path and name, and returns the name
+// it's imported as, once named.
+func (im *imports) add(path, name string) string {
+	if _, ok := im.pkgNames[path]; !ok {
+		im.paths = append(im.paths, path)
+		im.pkgNames[path] = name
+	}
+	return im.names[path]
+}
+
+// reserve reserves names used by other identifiers.
+func (im *imports) reserve(names ...string) {
+	for _, name := range names {
+		im.reserved[name] = true
+	}
+}
+
+// name names the packages in the order they were added, so packages
+// used by the mock itself keep their names.
+func (im *imports) name() {
+	for _, p := range im.paths {
+		base := im.pkgNames[p]
+		name := base
+		for i := 2; im.reserved[name]; i++ {
+			name = fmt.Sprintf("%s%d", base, i)
+		}
+		im.reserved[name] = true
+		im.names[p] = name
+	}
+}
+
+// specs returns the import specs sorted by path, those of the standard
+// library first and the others after an empty spec.
+func (im *imports) specs() []string {
+	var std, others []string
+	for _, p := range slices.Sorted(slices.Values(im.paths)) {
+		spec := fmt.Sprintf("%q", p)
+		if name := im.names[p]; name != path.Base(p) {
+			spec = name + " " + spec
+		}
+		if strings.Contains(strings.Split(p, "/")[0], ".") {
+			others = append(others, spec)
+		} else {
+			std = append(std, spec)
+		}
+	}
+	if len(std) > 0 && len(others) > 0 {
+		std = append(std, "")
+	}
+	return append(std, others...)
+}
+
+// mockFile is the data of the mock template.
+type mockFile struct {
+	Package   string
+	Imports   []string
+	Mock      string
+	Interface string
+	Name      string
+	Methods   []method
+}
+
+type method struct {
+	Name string
+	// Title is the name starting in upper case, as used in the names of
+	// the expectations of the method.
+	Title   string
+	Params  []variable
+	Results []variable
+	// Variadic tells whether the last parameter is variadic.
+	Variadic bool
+}
+
+type variable struct {
+	Name string
+	Type string
+}
+
+func newMethod(fn *types.Func, qualifier types.Qualifier, im *imports) method {
+	sig := fn.Type().(*types.Signature)
+	m := method{Name: fn.Name(), Variadic: sig.Variadic()}
+	m.Title = title(m.Name)
+	taken := map[string]bool{"m": true, "results": true}
+	for _, name := range im.names {
+		taken[name] = true
+	}
+	for i := range sig.Results().Len() {
+		r := variable{fmt.Sprintf("r%d", i), types.TypeString(sig.Results().At(i).Type(), qualifier)}
+		m.Results = append(m.Results, r)
+		taken[r.Name] = true
+	}
+	for i := range sig.Params().Len() {
+		p := sig.Params().At(i)
+		name := p.Name()
+		for j := i; name == "" || name == "_" || taken[name]; j++ {
+			name = fmt.Sprintf("p%d", j)
+		}
+		taken[name] = true
+		typ := types.TypeString(p.Type(), qualifier)
+		if m.Variadic && i == sig.Params().Len()-1 {
+			typ = "..." + types.TypeString(p.Type().(*types.Slice).Elem(), qualifier)
+		}
+		m.Params = append(m.Params, variable{name, typ})
+	}
+	return m
+}
+
+// title returns name starting in upper case.
+func title(name string) string {
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// Signature returns the parameters of the method, as declared.
+func (m method) Signature() string {
+	s := make([]string, len(m.Params))
+	for i, p := range m.Params {
+		s[i] = p.Name + " " + p.Type
+	}
+	return strings.Join(s, ", ")
+}
+
+// Args returns the parameters as arguments of a call.
+func (m method) Args() string {
+	s := make([]string, len(m.Params))
+	for i, p := range m.Params {
+		s[i] = p.Name
+	}
+	return strings.Join(s, ", ")
+}
+
+// ResultSignature returns the named results of the method, as declared.
+func (m method) ResultSignature() string {
+	s := make([]string, len(m.Results))
+	for i, r := range m.Results {
+		s[i] = r.Name + " " + r.Type
+	}
+	return strings.Join(s, ", ")
+}
+
+// ResultArgs returns the results as arguments of a call.
+func (m method) ResultArgs() string {
+	s := make([]string, len(m.Results))
+	for i, r := range m.Results {
+		s[i] = r.Name
+	}
+	return strings.Join(s, ", ")
+}
+
+var mockTemplate = template.Must(template.New("mock").Parse(`// Code generated by github.com/xandalm/go-testing/cmd; DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{- range .Imports}}
+	{{.}}
+{{- end}}
+)
+
+// {{.Name}} is a mock of {{.Interface}}.
+type {{.Name}} struct {
+	*{{.Mock}}.Expectations
+}
+
+// New{{.Name}} returns a mock of {{.Interface}} expecting no calls.
+func New{{.Name}}() *{{.Name}} {
+	return &{{.Name}}{ {{- .Mock}}.NewExpectations("{{.Interface}}")}
+}
+{{range .Methods}}{{$call := print $.Name .Title "Call"}}
+// {{$call}} is an expected call of {{.Name}}.
+type {{$call}} struct {
+	*{{$.Mock}}.Expected
+}
+
+// Expect{{.Title}} expects a call of {{.Name}} with the given arguments.
+func (m *{{$.Name}}) Expect{{.Title}}({{.Signature}}) *{{$call}} {
+	return &{{$call}}{m.Expectations.Expect("{{.Name}}"{{if .Params}}, {{.Args}}{{end}})}
+}
+{{if .Results}}
+// Return sets the results of the call.
+func (c *{{$call}}) Return({{.ResultSignature}}) *{{$call}} {
+	c.Expected.Return({{.ResultArgs}})
+	return c
+}
+{{end}}
+// Times sets the number of calls expected, or any number if n is negative.
+func (c *{{$call}}) Times(n int) *{{$call}} {
+	c.Expected.Times(n)
+	return c
+}
+
+// AnyArgs makes calls with any arguments match.
+func (c *{{$call}}) AnyArgs() *{{$call}} {
+	c.Expected.AnyArgs()
+	return c
+}
+
+func (m *{{$.Name}}) {{.Name}}({{.Signature}}){{if .Results}} ({{.ResultSignature}}){{end}} {
+	{{if .Results}}results := {{end}}m.Expectations.Call("{{.Name}}"{{if .Params}}, {{.Args}}{{end}})
+	{{- if .Results}}
+	if results != nil {
+		{{- range $i, $r := .Results}}
+		{{$r.Name}}, _ = results[{{$i}}].({{$r.Type}})
+		{{- end}}
+	}
+	return
+	{{- end}}
+}
+{{end}}`))
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/xandalm/go-testing/assert"
+)
+
+func TestGenerate(t *testing.T) {
+	t.Run("matches the generated file", func(t *testing.T) {
+		got, err := generate("testdata/store", "Store", options{})
+		assert.Nil(t, err)
+		want, err := os.ReadFile("testdata/store/mock_store.go")
+		assert.Nil(t, err)
+		assert.Equal(t, string(got), string(want), "mock_store.go is outdated, run go generate in testdata/store")
+	})
+
+	t.Run("generated mock works", func(t *testing.T) {
+		out, err := exec.Command("go", "test", "./testdata/store").CombinedOutput()
+		assert.Nil(t, err, "go test failed:\n%s", out)
+	})
+
+	t.Run("external package", func(t *testing.T) {
+		got, err := generate("testdata/mock", "Timer", options{name: "FakeTimer", pkg: "mock_test"})
+		assert.Nil(t, err)
+		assert.ContainsLines(t, string(got), []string{
+			"package mock_test",
+			`	mock2 "github.com/xandalm/go-testing/cmd/testdata/mock"`,
+			"// FakeTimer is a mock of Timer.",
+			"func (m *FakeTimer) Reset(d time.Duration, c mock2.Clock) (r0 bool) {",
+		})
+	})
+
+	t.Run("renames parameters", func(t *testing.T) {
+		got, err := generate("testdata/mock", "Clock", options{})
+		assert.Nil(t, err)
+		assert.ContainsLines(t, string(got), []string{
+			`	"github.com/xandalm/go-testing/assert/mock"`,
+			"func (m *MockClock) Sleep(p0 time.Duration) {",
+			"func (m *MockClock) ExpectUnexported() *MockClockUnexportedCall {",
+			"func (m *MockClock) unexported() {",
+		})
+	})
+
+	for _, c := range []struct {
+		iface string
+		pkg   string
+		want  string
+	}{
+		{"Missing", "", "Missing not found in package mock"},
+		{"NotInterface", "", "NotInterface isn't an interface"},
+		{"Generic", "", "Generic is generic, which isn't supported"},
+		{"Clock", "other", "Clock has the unexported method unexported, which can't be implemented outside its package"},
+		{"Verifier", "", "Verifier has the method Verify, which collides with the mock.Expectations embedded in the mock"},
+		{"Pinger", "", "Pinger has the method ExpectPing, which collides with the method of the mock expecting calls of ping"},
+	} {
+		t.Run(c.iface, func(t *testing.T) {
+			_, err := generate("testdata/mock", c.iface, options{pkg: c.pkg})
+			assert.NotNil(t, err)
+			if err != nil && !strings.Contains(err.Error(), c.want) {
+				t.Errorf("expected error %q, got %q", c.want, err)
+			}
+		})
+	}
+}
@@ -1,14 +1,64 @@
+// Command cmd generates mocks of interfaces, reading the package declaring
+// them from source. The mocks record their calls and check them against
+// the expected ones with the assert/mock package:
+//
+//	checker := NewMockAvailabilityChecker()
+//	checker.ExpectPing().Return(nil).Times(2)
+//	...
+//	checker.Verify(t)
+//
+// Usage:
+//
+//	cmd [-o file] [-name type] [-pkg name] dir interface
+//
+// The generated files are deterministic and marked as generated, to be
+// regenerated with go generate:
+//
+//	//go:generate go run github.com/xandalm/go-testing/cmd -o mock_checker_test.go -pkg testing_test . AvailabilityChecker
 package main
 
 import (
+	"flag"
 	"fmt"
-	"log"
-	"net/http"
+	"io"
+	"os"
 )
 
 func main() {
-	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		fmt.Fprint(w, "Hi there")
-	})
-	log.Fatal(http.ListenAndServe(":5000", handler))
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run runs the command with args, returning its exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("cmd", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: cmd [-o file] [-name type] [-pkg name] dir interface")
+		fs.PrintDefaults()
+	}
+	out := fs.String("o", "", "write the mock to `file` instead of the standard output")
+	var opts options
+	fs.StringVar(&opts.name, "name", "", "name of the mock `type` (default Mock and the interface name)")
+	fs.StringVar(&opts.pkg, "pkg", "", "`name` of the package of the mock (default the interface's package)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return 2
+	}
+
+	src, err := generate(fs.Arg(0), fs.Arg(1), opts)
+	if err == nil {
+		if *out == "" {
+			_, err = stdout.Write(src)
+		} else {
+			err = os.WriteFile(*out, src, 0o644)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "cmd: %v\n", err)
+		return 1
+	}
+	return 0
 }
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xandalm/go-testing/assert"
+)
+
+func TestRun(t *testing.T) {
+	t.Run("writes the file", func(t *testing.T) {
+		out := filepath.Join(t.TempDir(), "mock.go")
+		var stdout, stderr bytes.Buffer
+		code := run([]string{"-o", out, "-name", "FakeStore", "testdata/store", "Store"}, &stdout, &stderr)
+		assert.Equal(t, code, 0, "exit code %d, stderr:\n%s", code, &stderr)
+		assert.Equal(t, stdout.Len(), 0)
+
+		b, err := os.ReadFile(out)
+		assert.Nil(t, err)
+		assert.HasPrefix(t, string(b), "// Code generated by github.com/xandalm/go-testing/cmd; DO NOT EDIT.\n")
+		assert.Contains(t, string(b), "type FakeStore struct {")
+	})
+
+	t.Run("writes to stdout", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		assert.Equal(t, run([]string{"testdata/mock", "Timer"}, &stdout, &stderr), 0)
+		assert.Contains(t, stdout.String(), "type MockTimer struct {")
+	})
+
+	t.Run("usage", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		assert.Equal(t, run([]string{"testdata/store"}, &stdout, &stderr), 2)
+		assert.HasPrefix(t, stderr.String(), "usage: cmd [-o file] [-name type] [-pkg name] dir interface\n")
+	})
+
+	t.Run("error", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		assert.Equal(t, run([]string{"testdata/mock", "Missing"}, &stdout, &stderr), 1)
+		assert.Equal(t, strings.TrimSpace(stderr.String()), "cmd: Missing not found in package mock")
+	})
+}
@@ -0,0 +1,30 @@
+// Package mock has the name of the package of the generated mocks, to
+// test mocks of its interfaces are generated with aliased imports.
+package mock
+
+import "time"
+
+type Clock interface {
+	Now() time.Time
+	Sleep(mock time.Duration)
+	unexported()
+}
+
+type Timer interface {
+	Reset(d time.Duration, c Clock) bool
+}
+
+type Verifier interface {
+	Verify() error
+}
+
+type Pinger interface {
+	ping()
+	ExpectPing()
+}
+
+type Generic[T any] interface {
+	Get() T
+}
+
+type NotInterface struct{}
@@ -0,0 +1,228 @@
+// Code generated by github.com/xandalm/go-testing/cmd; DO NOT EDIT.
+
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/xandalm/go-testing/assert/mock"
+)
+
+// MockStore is a mock of Store.
+type MockStore struct {
+	*mock.Expectations
+}
+
+// NewMockStore returns a mock of Store expecting no calls.
+func NewMockStore() *MockStore {
+	return &MockStore{mock.NewExpectations("Store")}
+}
+
+// MockStoreCloseCall is an expected call of Close.
+type MockStoreCloseCall struct {
+	*mock.Expected
+}
+
+// ExpectClose expects a call of Close with the given arguments.
+func (m *MockStore) ExpectClose() *MockStoreCloseCall {
+	return &MockStoreCloseCall{m.Expectations.Expect("Close")}
+}
+
+// Return sets the results of the call.
+func (c *MockStoreCloseCall) Return(r0 error) *MockStoreCloseCall {
+	c.Expected.Return(r0)
+	return c
+}
+
+// Times sets the number of calls expected, or any number if n is negative.
+func (c *MockStoreCloseCall) Times(n int) *MockStoreCloseCall {
+	c.Expected.Times(n)
+	return c
+}
+
+// AnyArgs makes calls with any arguments match.
+func (c *MockStoreCloseCall) AnyArgs() *MockStoreCloseCall {
+	c.Expected.AnyArgs()
+	return c
+}
+
+func (m *MockStore) Close() (r0 error) {
+	results := m.Expectations.Call("Close")
+	if results != nil {
+		r0, _ = results[0].(error)
+	}
+	return
+}
+
+// MockStoreGetCall is an expected call of Get.
+type MockStoreGetCall struct {
+	*mock.Expected
+}
+
+// ExpectGet expects a call of Get with the given arguments.
+func (m *MockStore) ExpectGet(ctx context.Context, key string) *MockStoreGetCall {
+	return &MockStoreGetCall{m.Expectations.Expect("Get", ctx, key)}
+}
+
+// Return sets the results of the call.
+func (c *MockStoreGetCall) Return(r0 Item, r1 error) *MockStoreGetCall {
+	c.Expected.Return(r0, r1)
+	return c
+}
+
+// Times sets the number of calls expected, or any number if n is negative.
+func (c *MockStoreGetCall) Times(n int) *MockStoreGetCall {
+	c.Expected.Times(n)
+	return c
+}
+
+// AnyArgs makes calls with any arguments match.
+func (c *MockStoreGetCall) AnyArgs() *MockStoreGetCall {
+	c.Expected.AnyArgs()
+	return c
+}
+
+func (m *MockStore) Get(ctx context.Context, key string) (r0 Item, r1 error) {
+	results := m.Expectations.Call("Get", ctx, key)
+	if results != nil {
+		r0, _ = results[0].(Item)
+		r1, _ = results[1].(error)
+	}
+	return
+}
+
+// MockStoreKeysCall is an expected call of Keys.
+type MockStoreKeysCall struct {
+	*mock.Expected
+}
+
+// ExpectKeys expects a call of Keys with the given arguments.
+func (m *MockStore) ExpectKeys(prefix string, limit int) *MockStoreKeysCall {
+	return &MockStoreKeysCall{m.Expectations.Expect("Keys", prefix, limit)}
+}
+
+// Return sets the results of the call.
+func (c *MockStoreKeysCall) Return(r0 []string) *MockStoreKeysCall {
+	c.Expected.Return(r0)
+	return c
+}
+
+// Times sets the number of calls expected, or any number if n is negative.
+func (c *MockStoreKeysCall) Times(n int) *MockStoreKeysCall {
+	c.Expected.Times(n)
+	return c
+}
+
+// AnyArgs makes calls with any arguments match.
+func (c *MockStoreKeysCall) AnyArgs() *MockStoreKeysCall {
+	c.Expected.AnyArgs()
+	return c
+}
+
+func (m *MockStore) Keys(prefix string, limit int) (r0 []string) {
+	results := m.Expectations.Call("Keys", prefix, limit)
+	if results != nil {
+		r0, _ = results[0].([]string)
+	}
+	return
+}
+
+// MockStoreLogfCall is an expected call of Logf.
+type MockStoreLogfCall struct {
+	*mock.Expected
+}
+
+// ExpectLogf expects a call of Logf with the given arguments.
+func (m *MockStore) ExpectLogf(format string, args ...any) *MockStoreLogfCall {
+	return &MockStoreLogfCall{m.Expectations.Expect("Logf", format, args)}
+}
+
+// Times sets the number of calls expected, or any number if n is negative.
+func (c *MockStoreLogfCall) Times(n int) *MockStoreLogfCall {
+	c.Expected.Times(n)
+	return c
+}
+
+// AnyArgs makes calls with any arguments match.
+func (c *MockStoreLogfCall) AnyArgs() *MockStoreLogfCall {
+	c.Expected.AnyArgs()
+	return c
+}
+
+func (m *MockStore) Logf(format string, args ...any) {
+	m.Expectations.Call("Logf", format, args)
+}
+
+// MockStorePutCall is an expected call of Put.
+type MockStorePutCall struct {
+	*mock.Expected
+}
+
+// ExpectPut expects a call of Put with the given arguments.
+func (m *MockStore) ExpectPut(p0 context.Context, p1 Item, p2 time.Duration) *MockStorePutCall {
+	return &MockStorePutCall{m.Expectations.Expect("Put", p0, p1, p2)}
+}
+
+// Return sets the results of the call.
+func (c *MockStorePutCall) Return(r0 error) *MockStorePutCall {
+	c.Expected.Return(r0)
+	return c
+}
+
+// Times sets the number of calls expected, or any number if n is negative.
+func (c *MockStorePutCall) Times(n int) *MockStorePutCall {
+	c.Expected.Times(n)
+	return c
+}
+
+// AnyArgs makes calls with any arguments match.
+func (c *MockStorePutCall) AnyArgs() *MockStorePutCall {
+	c.Expected.AnyArgs()
+	return c
+}
+
+func (m *MockStore) Put(p0 context.Context, p1 Item, p2 time.Duration) (r0 error) {
+	results := m.Expectations.Call("Put", p0, p1, p2)
+	if results != nil {
+		r0, _ = results[0].(error)
+	}
+	return
+}
+
+// MockStoreWatchCall is an expected call of Watch.
+type MockStoreWatchCall struct {
+	*mock.Expected
+}
+
+// ExpectWatch expects a call of Watch with the given arguments.
+func (m *MockStore) ExpectWatch(p0 map[string]bool) *MockStoreWatchCall {
+	return &MockStoreWatchCall{m.Expectations.Expect("Watch", p0)}
+}
+
+// Return sets the results of the call.
+func (c *MockStoreWatchCall) Return(r0 <-chan Item, r1 func()) *MockStoreWatchCall {
+	c.Expected.Return(r0, r1)
+	return c
+}
+
+// Times sets the number of calls expected, or any number if n is negative.
+func (c *MockStoreWatchCall) Times(n int) *MockStoreWatchCall {
+	c.Expected.Times(n)
+	return c
+}
+
+// AnyArgs makes calls with any arguments match.
+func (c *MockStoreWatchCall) AnyArgs() *MockStoreWatchCall {
+	c.Expected.AnyArgs()
+	return c
+}
+
+func (m *MockStore) Watch(p0 map[string]bool) (r0 <-chan Item, r1 func()) {
+	results := m.Expectations.Call("Watch", p0)
+	if results != nil {
+		r0, _ = results[0].(<-chan Item)
+		r1, _ = results[1].(func())
+	}
+	return
+}
@@ -0,0 +1,25 @@
+// Package store declares an interface mocked by the tests of the cmd
+// command.
+package store
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+//go:generate go run github.com/xandalm/go-testing/cmd -o mock_store.go . Store
+
+type Item struct {
+	Key   string
+	Value []byte
+}
+
+type Store interface {
+	io.Closer
+	Get(ctx context.Context, key string) (Item, error)
+	Put(context.Context, Item, time.Duration) error
+	Keys(prefix string, limit int) []string
+	Logf(format string, args ...any)
+	Watch(m map[string]bool) (<-chan Item, func())
+}
@@ -0,0 +1,37 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/xandalm/go-testing/assert"
+)
+
+func TestMockStore(t *testing.T) {
+	ctx := context.Background()
+	errNotFound := errors.New("not found")
+
+	s := NewMockStore()
+	s.ExpectGet(ctx, "a").Return(Item{"a", []byte("1")}, nil)
+	s.ExpectGet(ctx, "b").Return(Item{}, errNotFound)
+	s.ExpectPut(ctx, Item{"b", nil}, time.Minute)
+	s.ExpectKeys("", 0).AnyArgs().Return([]string{"a"}).Times(-1)
+	s.ExpectLogf("put %s", "b")
+	s.ExpectClose().Return(nil)
+
+	item, err := s.Get(ctx, "a")
+	assert.Equal(t, item, Item{"a", []byte("1")})
+	assert.Nil(t, err)
+	_, err = s.Get(ctx, "b")
+	assert.Error(t, err, errNotFound)
+	assert.Nil(t, s.Put(ctx, Item{"b", nil}, time.Minute))
+	s.Logf("put %s", "b")
+	assert.Equal(t, s.Keys("x", 2), []string{"a"})
+	assert.Equal(t, s.Keys("y", 1), []string{"a"})
+	assert.Nil(t, s.Close())
+
+	s.Verify(t)
+	assert.Equal(t, len(s.Calls()), 7)
+}
@@ -0,0 +1,238 @@
+// Package expect drives interactive programs, such as prompts, password
+// inputs and REPLs, through a pseudo-terminal, in the manner of expect:
+//
+//	c := expect.Start(t, exec.Command("./cli"))
+//	c.Expect(`name\? `, time.Second)
+//	c.SendLine("gopher")
+//	c.Expect(`hello, gopher`, time.Second)
+//	c.ExpectExit(0, time.Second)
+//
+// The program runs with the terminal as its standard input, output and
+// error, and as its controlling terminal, so it behaves as run by a user
+// rather than with pipes. The terminal echoes what is sent and ends the
+// output lines with "\r\n".
+//
+// Pseudo-terminals are only supported on Linux.
+package expect
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// Console is a program running in a pseudo-terminal.
+type Console struct {
+	t   testing.TB
+	cmd *exec.Cmd
+	pty *os.File
+
+	mu sync.Mutex
+	// out is the output not matched by Expect yet.
+	out        []byte
+	transcript []byte
+	// readErr ends the output, io.EOF once the terminal is closed.
+	readErr error
+	// changed is closed, and replaced, when the output changes.
+	changed  chan struct{}
+	readDone chan struct{}
+
+	exited  chan struct{}
+	waitErr error
+}
+
+// Start starts cmd attached to a new pseudo-terminal. The program is killed
+// when the test ends, if it didn't exit before.
+func Start(t testing.TB, cmd *exec.Cmd) *Console {
+	t.Helper()
+
+	if cmd == nil {
+		panic("expect: nil command")
+	}
+	master, slave, err := openPTY()
+	if err != nil {
+		t.Fatalf("expect: cannot open pseudo-terminal, %v", err)
+	}
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = slave, slave, slave
+	attach(cmd)
+	err = cmd.Start()
+	// the program holds the terminal now, which ends when it's closed
+	// by the program and its children
+	slave.Close()
+	if err != nil {
+		master.Close()
+		t.Fatalf("expect: cannot start %s, %v", cmd.Path, err)
+	}
+
+	c := &Console{
+		t:        t,
+		cmd:      cmd,
+		pty:      master,
+		changed:  make(chan struct{}),
+		readDone: make(chan struct{}),
+		exited:   make(chan struct{}),
+	}
+	go c.read()
+	go func() {
+		c.waitErr = cmd.Wait()
+		close(c.exited)
+	}()
+	t.Cleanup(c.stop)
+	return c
+}
+
+func (c *Console) read() {
+	defer close(c.readDone)
+	buf := make([]byte, 4096)
+	for {
+		n, err := c.pty.Read(buf)
+
+		c.mu.Lock()
+		c.out = append(c.out, buf[:n]...)
+		c.transcript = append(c.transcript, buf[:n]...)
+		if err != nil {
+			// reading a terminal closed on the other side fails with EIO
+			if errors.Is(err, syscall.EIO) || errors.Is(err, os.ErrClosed) {
+				err = io.EOF
+			}
+			c.readErr = err
+		}
+		close(c.changed)
+		c.changed = make(chan struct{})
+		c.mu.Unlock()
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+// stop kills the program, if still running, and closes the terminal.
+func (c *Console) stop() {
+	select {
+	case <-c.exited:
+	default:
+		c.cmd.Process.Kill()
+		<-c.exited
+	}
+	c.pty.Close()
+	<-c.readDone
+}
+
+// Expect waits for the output to match the regular expression pattern, for
+// at most timeout, and returns the match and its submatches. The output up
+// to the end of the match is consumed, so it isn't matched again.
+func (c *Console) Expect(pattern string, timeout time.Duration) []string {
+	c.t.Helper()
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		c.t.Fatalf("expect: %v", err)
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		c.mu.Lock()
+		if loc := re.FindSubmatchIndex(c.out); loc != nil {
+			m := make([]string, len(loc)/2)
+			for i := range m {
+				if loc[2*i] >= 0 {
+					m[i] = string(c.out[loc[2*i]:loc[2*i+1]])
+				}
+			}
+			c.out = c.out[loc[1]:]
+			c.mu.Unlock()
+			return m
+		}
+		readErr, changed := c.readErr, c.changed
+		c.mu.Unlock()
+
+		switch {
+		case readErr == io.EOF:
+			c.t.Fatalf("no match for %#q before the end of output\n%s", pattern, c.report())
+			return nil
+		case readErr != nil:
+			c.t.Fatalf("no match for %#q, %v\n%s", pattern, readErr, c.report())
+			return nil
+		}
+		select {
+		case <-changed:
+		case <-timer.C:
+			c.t.Fatalf("no match for %#q within %v\n%s", pattern, timeout, c.report())
+			return nil
+		}
+	}
+}
+
+// Send writes input to the terminal, as typed by the user.
+func (c *Console) Send(input string) {
+	c.t.Helper()
+
+	if _, err := io.WriteString(c.pty, input); err != nil {
+		c.t.Fatalf("expect: cannot send %q, %v", input, err)
+	}
+}
+
+// SendLine sends line followed by the Enter key.
+func (c *Console) SendLine(line string) {
+	c.t.Helper()
+
+	c.Send(line + "\r")
+}
+
+// Transcript returns all the output of the terminal so far, echoed input
+// included.
+func (c *Console) Transcript() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return string(c.transcript)
+}
+
+// ExpectExit waits for the program to exit, for at most timeout, and
+// asserts it exits with code. A program ended by a signal exits with -1.
+func (c *Console) ExpectExit(code int, timeout time.Duration) {
+	c.t.Helper()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-c.exited:
+	case <-timer.C:
+		c.t.Fatalf("expected %s to exit within %v\n%s", c.cmd.Path, timeout, c.report())
+		return
+	}
+	// let the last output in the transcript, unless the terminal is still
+	// held by a child of the program
+	select {
+	case <-c.readDone:
+	case <-timer.C:
+	}
+
+	got := 0
+	var exit *exec.ExitError
+	switch {
+	case errors.As(c.waitErr, &exit):
+		got = exit.ExitCode()
+	case c.waitErr != nil:
+		c.t.Fatalf("expect: cannot wait for %s, %v", c.cmd.Path, c.waitErr)
+		return
+	}
+	if got != code {
+		c.t.Fatalf("expected %s to exit with code %d, but got %d\n%s", c.cmd.Path, code, got, c.report())
+	}
+}
+
+// report returns the transcript as shown by failures.
+func (c *Console) report() string {
+	transcript := c.Transcript()
+	if transcript == "" {
+		return "[no output]"
+	}
+	return "[transcript]\n" + transcript
+}
@@ -0,0 +1,117 @@
+//go:build linux
+
+package expect_test
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xandalm/go-testing/assert/asserttest"
+	"github.com/xandalm/go-testing/expect"
+)
+
+// programEnv makes the test binary run as the greet program, given the
+// exit code.
+const programEnv = "EXPECT_GREET"
+
+func TestMain(m *testing.M) {
+	if code := os.Getenv(programEnv); code != "" {
+		n, _ := strconv.Atoi(code)
+		os.Exit(greet(n))
+	}
+	os.Exit(m.Run())
+}
+
+// greet greets the user, asking for a name only when run by a terminal.
+func greet(code int) int {
+	info, err := os.Stdin.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		fmt.Println("not a terminal")
+		return 1
+	}
+	fmt.Print("name? ")
+	name, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return 1
+	}
+	fmt.Printf("hello, %s\n", strings.TrimSpace(name))
+	return code
+}
+
+func command(code int) *exec.Cmd {
+	cmd := exec.Command(os.Args[0])
+	cmd.Env = append(os.Environ(), programEnv+"="+strconv.Itoa(code))
+	return cmd
+}
+
+func TestConsole(t *testing.T) {
+	c := expect.Start(t, command(3))
+	c.Expect(`name\? `, 5*time.Second)
+	c.SendLine("gopher")
+	m := c.Expect(`hello, (\w+)\r\n`, 5*time.Second)
+	if len(m) != 2 || m[1] != "gopher" {
+		t.Errorf("expected the name submatch, got %q", m)
+	}
+	c.ExpectExit(3, 5*time.Second)
+
+	if got, want := c.Transcript(), "name? gopher\r\nhello, gopher\r\n"; got != want {
+		t.Errorf("expected transcript %q, got %q", want, got)
+	}
+}
+
+func TestConsoleFailures(t *testing.T) {
+	cases := []struct {
+		name string
+		fn   func(c *expect.Console)
+		want []string
+	}{
+		{"timeout", func(c *expect.Console) {
+			c.Expect(`password: `, 100*time.Millisecond)
+		}, []string{"no match for `password: ` within 100ms\n[transcript]\nname? "}},
+		{"end of output", func(c *expect.Console) {
+			c.Expect(`name\? `, 5*time.Second)
+			c.SendLine("gopher")
+			c.Expect(`bye`, 5*time.Second)
+		}, []string{"no match for `bye` before the end of output\n[transcript]\nname? gopher\r\nhello, gopher\r\n"}},
+		{"exit code", func(c *expect.Console) {
+			c.Expect(`name\? `, 5*time.Second)
+			c.SendLine("gopher")
+			c.ExpectExit(0, 5*time.Second)
+		}, []string{"to exit with code 0, but got 3\n[transcript]\n"}},
+		{"exit timeout", func(c *expect.Console) {
+			c.ExpectExit(3, 100*time.Millisecond)
+		}, []string{"to exit within 100ms\n[transcript]\nname? "}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := asserttest.ExpectFailure(t, func(t testing.TB) {
+				c.fn(expect.Start(t, command(3)))
+			})
+			msgs := r.Messages("Fatal")
+			if len(msgs) != 1 {
+				t.Fatalf("expected one failure, got %q", msgs)
+			}
+			for _, want := range c.want {
+				if !strings.Contains(msgs[0], want) {
+					t.Errorf("message should contain %q, got:\n%s", want, msgs[0])
+				}
+			}
+		})
+	}
+}
+
+func TestStartFailure(t *testing.T) {
+	r := asserttest.ExpectFailure(t, func(t testing.TB) {
+		expect.Start(t, exec.Command("testdata/missing"))
+	})
+	msgs := r.Messages("Fatal")
+	if len(msgs) != 1 || !strings.HasPrefix(msgs[0], "expect: cannot start testdata/missing") {
+		t.Errorf("expected a start failure, got %q", msgs)
+	}
+}
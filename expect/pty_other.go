@@ -0,0 +1,15 @@
+//go:build !linux
+
+package expect
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+)
+
+func openPTY() (master, slave *os.File, err error) {
+	return nil, nil, errors.New("pseudo-terminals are only supported on Linux")
+}
+
+func attach(cmd *exec.Cmd) {}
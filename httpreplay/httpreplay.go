@@ -0,0 +1,367 @@
+// Package httpreplay records the HTTP interactions of a client with a real
+// server to a cassette file, and replays them afterwards, so client tests
+// run without launching the server:
+//
+//	tr := httpreplay.New(t, "testdata/users.json", httpreplay.Redact("Authorization"))
+//	client := &http.Client{Transport: tr}
+//
+// Run with RecordEnv set, the requests reach the server, such as one
+// started by ServerLauncher, and the cassette is written when the test
+// ends, unless it failed. Otherwise, they're answered from the cassette,
+// and the requests it doesn't hold fail the test.
+package httpreplay
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"unicode/utf8"
+)
+
+// RecordEnv is the environment variable which, set to a non-empty value,
+// makes transports record their cassettes instead of replaying them, unless
+// given Replay.
+const RecordEnv = "HTTPREPLAY_RECORD"
+
+// redacted replaces the values of the redacted headers in cassettes.
+const redacted = "[REDACTED]"
+
+// Option configures a Transport.
+type Option func(*Transport)
+
+// mode is whether a transport records or replays.
+type mode int
+
+const (
+	// modeEnv records if RecordEnv is set, and replays otherwise.
+	modeEnv mode = iota
+	modeRecord
+	modeReplay
+)
+
+// Record makes the transport record, regardless of RecordEnv.
+func Record() Option {
+	return func(tr *Transport) {
+		tr.mode = modeRecord
+	}
+}
+
+// Replay makes the transport replay, regardless of RecordEnv, for tests
+// whose cassettes mustn't be recorded again.
+func Replay() Option {
+	return func(tr *Transport) {
+		tr.mode = modeReplay
+	}
+}
+
+// Redact replaces the values of the headers named by keys, such as
+// credentials, in the cassette.
+func Redact(keys ...string) Option {
+	return func(tr *Transport) {
+		for _, key := range keys {
+			tr.redact = append(tr.redact, http.CanonicalHeaderKey(key))
+		}
+	}
+}
+
+// Passthrough makes the requests not recorded in the cassette reach the
+// server when replaying, rather than fail the test. Each one is logged.
+func Passthrough() Option {
+	return func(tr *Transport) {
+		tr.passthrough = true
+	}
+}
+
+// Upstream sets the transport the requests reach the server with,
+// http.DefaultTransport by default.
+func Upstream(rt http.RoundTripper) Option {
+	if rt == nil {
+		panic("httpreplay: nil upstream transport")
+	}
+	return func(tr *Transport) {
+		tr.upstream = rt
+	}
+}
+
+// Transport is an http.RoundTripper recording or replaying a cassette.
+type Transport struct {
+	t           testing.TB
+	file        string
+	mode        mode
+	record      bool
+	passthrough bool
+	redact      []string
+	upstream    http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []*Interaction
+	// replayed tells the interactions already replayed, by index.
+	replayed []bool
+}
+
+// New returns a transport for the cassette file, usually in testdata. When
+// recording, the cassette is written when the test ends, unless it failed.
+func New(t testing.TB, file string, opts ...Option) *Transport {
+	t.Helper()
+
+	tr := &Transport{t: t, file: file, upstream: http.DefaultTransport}
+	for _, opt := range opts {
+		opt(tr)
+	}
+	tr.record = tr.mode == modeRecord || tr.mode == modeEnv && os.Getenv(RecordEnv) != ""
+
+	if tr.record {
+		t.Cleanup(tr.save)
+		return tr
+	}
+	b, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("httpreplay: cannot read cassette, %v; record it with %s set", err, RecordEnv)
+	}
+	var c cassette
+	if err := json.Unmarshal(b, &c); err != nil {
+		t.Fatalf("httpreplay: invalid cassette %s, %v", file, err)
+	}
+	tr.interactions = c.Interactions
+	tr.replayed = make([]bool, len(c.Interactions))
+	return tr
+}
+
+// Client returns a client using the transport.
+func (tr *Transport) Client() *http.Client {
+	return &http.Client{Transport: tr}
+}
+
+// Interactions returns the interactions recorded, or replayable.
+func (tr *Transport) Interactions() []Interaction {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	is := make([]Interaction, len(tr.interactions))
+	for i, in := range tr.interactions {
+		is[i] = *in
+	}
+	return is
+}
+
+func (tr *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	if tr.record {
+		return tr.roundTripRecord(req, body)
+	}
+	if in := tr.find(req, body); in != nil {
+		return in.Response.response(req), nil
+	}
+	if tr.passthrough {
+		tr.t.Logf("httpreplay: no interaction recorded in %s for %s %s, passing it through", tr.file, req.Method, req.URL)
+		return tr.upstream.RoundTrip(withBody(req, body))
+	}
+	tr.t.Errorf("httpreplay: no interaction recorded in %s for %s %s", tr.file, req.Method, req.URL)
+	return nil, fmt.Errorf("httpreplay: no interaction recorded for %s %s", req.Method, req.URL)
+}
+
+func (tr *Transport) roundTripRecord(req *http.Request, body []byte) (*http.Response, error) {
+	res, err := tr.upstream.RoundTrip(withBody(req, body))
+	if err != nil {
+		return nil, err
+	}
+	resBody, err := readBody(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(resBody))
+
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tr.interactions = append(tr.interactions, &Interaction{
+		Request: Request{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: tr.redacted(req.Header),
+			Body:   newBody(body),
+		},
+		Response: Response{
+			Status: res.StatusCode,
+			Header: tr.redacted(res.Header),
+			Body:   newBody(resBody),
+		},
+	})
+	return res, nil
+}
+
+// find returns the first interaction not replayed yet matching the method,
+// URL and body of req, or else the last one replayed, as the server would
+// answer the same again.
+func (tr *Transport) find(req *http.Request, body []byte) *Interaction {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	last := -1
+	for i, in := range tr.interactions {
+		if !in.Request.matches(req, body) {
+			continue
+		}
+		if !tr.replayed[i] {
+			tr.replayed[i] = true
+			return in
+		}
+		last = i
+	}
+	if last < 0 {
+		return nil
+	}
+	return tr.interactions[last]
+}
+
+func (tr *Transport) redacted(h http.Header) http.Header {
+	h = h.Clone()
+	for _, key := range tr.redact {
+		if _, ok := h[key]; ok {
+			h[key] = []string{redacted}
+		}
+	}
+	return h
+}
+
+// save writes the recorded cassette, unless the test failed, as the
+// interactions may not be the expected ones.
+func (tr *Transport) save() {
+	if tr.t.Failed() {
+		tr.t.Logf("httpreplay: test failed, %s not recorded", tr.file)
+		return
+	}
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	b, err := json.MarshalIndent(cassette{tr.interactions}, "", "\t")
+	if err != nil {
+		tr.t.Errorf("httpreplay: cannot encode cassette, %v", err)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(tr.file), 0o755); err != nil {
+		tr.t.Errorf("httpreplay: cannot write cassette, %v", err)
+		return
+	}
+	if err := os.WriteFile(tr.file, append(b, '\n'), 0o644); err != nil {
+		tr.t.Errorf("httpreplay: cannot write cassette, %v", err)
+		return
+	}
+	tr.t.Logf("recorded %s", tr.file)
+}
+
+// readBody reads and closes the body of a request or a response.
+func readBody(rc io.ReadCloser) ([]byte, error) {
+	if rc == nil || rc == http.NoBody {
+		return nil, nil
+	}
+	b, err := io.ReadAll(rc)
+	rc.Close()
+	return b, err
+}
+
+// withBody returns a copy of req with body, already read from req, as
+// RoundTrip mustn't modify the request it's given.
+func withBody(req *http.Request, body []byte) *http.Request {
+	c := req.Clone(req.Context())
+	if req.Body == nil || req.Body == http.NoBody {
+		return c
+	}
+	c.Body = io.NopCloser(bytes.NewReader(body))
+	c.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	return c
+}
+
+type cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Interaction is a request and its response, as recorded in a cassette.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded request.
+type Request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   Body        `json:"body,omitempty"`
+}
+
+func (r Request) matches(req *http.Request, body []byte) bool {
+	return r.Method == req.Method && r.URL == req.URL.String() && bytes.Equal(r.Body, body)
+}
+
+// Response is a recorded response.
+type Response struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   Body        `json:"body,omitempty"`
+}
+
+func (r Response) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.Status, http.StatusText(r.Status)),
+		StatusCode:    r.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        r.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(r.Body)),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}
+}
+
+// Body is the body of a request or a response. It's kept in cassettes as a
+// string when it's text, or else as base64 prefixed by "base64:".
+type Body []byte
+
+// base64Prefix prefixes the bodies encoded in base64.
+const base64Prefix = "base64:"
+
+func newBody(b []byte) Body {
+	if len(b) == 0 {
+		return nil
+	}
+	return Body(slices.Clone(b))
+}
+
+func (b Body) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(b) && !strings.HasPrefix(string(b), base64Prefix) {
+		return json.Marshal(string(b))
+	}
+	return json.Marshal(base64Prefix + base64.StdEncoding.EncodeToString(b))
+}
+
+func (b *Body) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return errors.New("body must be a string")
+	}
+	if enc, ok := strings.CutPrefix(s, base64Prefix); ok {
+		dec, err := base64.StdEncoding.DecodeString(enc)
+		if err != nil {
+			return fmt.Errorf("invalid base64 body, %v", err)
+		}
+		*b = dec
+		return nil
+	}
+	*b = Body(s)
+	return nil
+}
@@ -0,0 +1,156 @@
+package httpreplay_test
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xandalm/go-testing/assert"
+	"github.com/xandalm/go-testing/assert/asserttest"
+	"github.com/xandalm/go-testing/assert/mockhttp"
+	"github.com/xandalm/go-testing/httpreplay"
+)
+
+func get(t testing.TB, c *http.Client, method, url, body string) (*http.Response, string) {
+	t.Helper()
+
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer secret")
+	res, err := c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res, string(b)
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	s := mockhttp.NewServer(t)
+	s.Expect("GET", "/users/1").Respond(http.StatusOK, "gopher").ResponseHeader("Set-Cookie", "session=1")
+	s.Expect("POST", "/users").Respond(http.StatusCreated, "created")
+	file := filepath.Join(t.TempDir(), "testdata", "users.json")
+
+	asserttest.ExpectSuccess(t, func(t testing.TB) {
+		c := httpreplay.New(t, file, httpreplay.Record(), httpreplay.Redact("authorization", "Set-Cookie")).Client()
+		res, body := get(t, c, "GET", s.URL+"/users/1", "")
+		assert.Equal(t, res.Header.Get("Set-Cookie"), "session=1")
+		assert.Equal(t, body, "gopher")
+		_, body = get(t, c, "POST", s.URL+"/users", `{"name":"gopher"}`)
+		assert.Equal(t, body, "created")
+	})
+	asserttest.ExpectSuccess(t, s.Verify)
+
+	b, err := os.ReadFile(file)
+	assert.Nil(t, err)
+	cassette := string(b)
+	assert.True(t, strings.Contains(cassette, `"[REDACTED]"`), "expected redacted headers in %s", cassette)
+	assert.False(t, strings.Contains(cassette, "secret"), "expected no credentials in %s", cassette)
+	assert.False(t, strings.Contains(cassette, "session=1"), "expected no cookie in %s", cassette)
+
+	tr := httpreplay.New(t, file, httpreplay.Replay())
+	assert.Equal(t, len(tr.Interactions()), 2)
+	c := tr.Client()
+	res, body := get(t, c, "POST", s.URL+"/users", `{"name":"gopher"}`)
+	assert.Equal(t, res.StatusCode, http.StatusCreated)
+	assert.Equal(t, body, "created")
+	res, body = get(t, c, "GET", s.URL+"/users/1", "")
+	assert.Equal(t, res.StatusCode, http.StatusOK)
+	assert.Equal(t, body, "gopher")
+	assert.Equal(t, len(s.Requests()), 2)
+}
+
+func TestRecordFailed(t *testing.T) {
+	s := mockhttp.NewServer(t)
+	s.Expect("GET", "/greeting").Respond(http.StatusOK, "Hi there")
+	file := filepath.Join(t.TempDir(), "greeting.json")
+
+	r := asserttest.ExpectFailure(t, func(t testing.TB) {
+		c := httpreplay.New(t, file, httpreplay.Record()).Client()
+		_, body := get(t, c, "GET", s.URL+"/greeting", "")
+		assert.Equal(t, body, "Hello")
+	})
+	assert.Equal(t, r.Messages("Log"), []string{"httpreplay: test failed, " + file + " not recorded"})
+	_, err := os.Stat(file)
+	assert.True(t, os.IsNotExist(err), "expected no cassette, got %v", err)
+}
+
+func TestReplay(t *testing.T) {
+	t.Setenv(httpreplay.RecordEnv, "1")
+	c := httpreplay.New(t, "testdata/greeting.json", httpreplay.Replay()).Client()
+
+	for range 2 {
+		res, body := get(t, c, "GET", "http://localhost:5000/greeting?lang=en", "")
+		assert.Equal(t, res.StatusCode, http.StatusOK)
+		assert.Equal(t, res.Header.Get("Content-Type"), "text/plain")
+		assert.Equal(t, body, "Hi there")
+	}
+	res, body := get(t, c, "POST", "http://localhost:5000/echo", "\x00\x01\x02")
+	assert.Equal(t, res.StatusCode, http.StatusCreated)
+	assert.Equal(t, body, "\x00\x01\x02")
+}
+
+func TestReplayUnmatched(t *testing.T) {
+	r := asserttest.ExpectFailure(t, func(t testing.TB) {
+		c := httpreplay.New(t, "testdata/greeting.json", httpreplay.Replay()).Client()
+		_, err := c.Get("http://localhost:5000/greeting?lang=fr")
+		assert.NotNil(t, err)
+	})
+	assert.Equal(t, r.Messages("Error"), []string{
+		"httpreplay: no interaction recorded in testdata/greeting.json for GET http://localhost:5000/greeting?lang=fr",
+	})
+}
+
+func TestReplayPassthrough(t *testing.T) {
+	s := mockhttp.NewServer(t)
+	s.Expect("GET", "/greeting")
+
+	r := asserttest.ExpectSuccess(t, func(t testing.TB) {
+		c := httpreplay.New(t, "testdata/greeting.json", httpreplay.Replay(), httpreplay.Passthrough()).Client()
+		res, _ := get(t, c, "GET", s.URL+"/greeting", "")
+		assert.Equal(t, res.StatusCode, http.StatusOK)
+	})
+	assert.Equal(t, r.Messages("Log"), []string{
+		"httpreplay: no interaction recorded in testdata/greeting.json for GET " + s.URL + "/greeting, passing it through",
+	})
+	asserttest.ExpectSuccess(t, s.Verify)
+}
+
+func TestMissingCassette(t *testing.T) {
+	r := asserttest.ExpectFailure(t, func(t testing.TB) {
+		httpreplay.New(t, "testdata/missing.json", httpreplay.Replay())
+	})
+	msgs := r.Messages("Fatal")
+	assert.Equal(t, len(msgs), 1)
+	assert.True(t, strings.HasSuffix(msgs[0], "record it with HTTPREPLAY_RECORD set"), msgs[0])
+}
+
+func TestRequestUntouched(t *testing.T) {
+	s := mockhttp.NewServer(t)
+	s.Expect("POST", "/users").JSON(`{"name":"gopher"}`).Times(2)
+
+	transports := map[string]*httpreplay.Transport{
+		"record":      httpreplay.New(t, filepath.Join(t.TempDir(), "users.json"), httpreplay.Record()),
+		"passthrough": httpreplay.New(t, "testdata/greeting.json", httpreplay.Replay(), httpreplay.Passthrough()),
+	}
+	for name, tr := range transports {
+		req, err := http.NewRequest("POST", s.URL+"/users", strings.NewReader(`{"name":"gopher"}`))
+		assert.Nil(t, err)
+		body := req.Body
+
+		res, err := tr.RoundTrip(req)
+		assert.Nil(t, err)
+		res.Body.Close()
+		assert.True(t, req.Body == body, "%s: RoundTrip shouldn't replace the request body", name)
+	}
+	asserttest.ExpectSuccess(t, s.Verify)
+}
@@ -0,0 +1,86 @@
+// Package caller locates the caller of a failing assertion, skipping the
+// functions marked as helpers as testing.T does.
+package caller
+
+import (
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+)
+
+type Helpers struct {
+	mu    sync.Mutex
+	funcs map[string]struct{}
+	order []string
+}
+
+// Mark records the function skip frames above the caller of Mark as helper.
+func (h *Helpers) Mark(skip int) {
+	var pc [1]uintptr
+	if runtime.Callers(skip+2, pc[:]) == 0 {
+		return
+	}
+	frame, _ := runtime.CallersFrames(pc[:]).Next()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.funcs == nil {
+		h.funcs = map[string]struct{}{}
+	}
+	if _, ok := h.funcs[frame.Function]; !ok {
+		h.funcs[frame.Function] = struct{}{}
+		h.order = append(h.order, frame.Function)
+	}
+}
+
+// Funcs returns the names of the functions marked as helpers, in the order
+// they were marked.
+func (h *Helpers) Funcs() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return slices.Clone(h.order)
+}
+
+// Frame returns the first frame, starting skip frames above the caller of
+// Frame, that isn't a helper and whose function doesn't start with any of
+// the given prefixes.
+func (h *Helpers) Frame(skip int, prefixes ...string) (runtime.Frame, bool) {
+	var pcs [50]uintptr
+	n := runtime.Callers(skip+2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for {
+		frame, more := frames.Next()
+		if _, ok := h.funcs[frame.Function]; !ok && !hasAnyPrefix(frame.Function, prefixes) {
+			return frame, true
+		}
+		if !more {
+			return runtime.Frame{}, false
+		}
+	}
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// Package returns the qualified name prefix, e.g. "example.com/pkg.", of
+// the package declaring fn.
+func Package(fn any) string {
+	name := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()).Name()
+	for i := strings.LastIndex(name, "/") + 1; i < len(name); i++ {
+		if name[i] == '.' {
+			return name[:i+1]
+		}
+	}
+	return name
+}
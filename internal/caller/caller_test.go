@@ -0,0 +1,32 @@
+package caller_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/xandalm/go-testing/internal/caller"
+)
+
+func TestPackage(t *testing.T) {
+	if got := caller.Package(caller.Package); got != "github.com/xandalm/go-testing/internal/caller." {
+		t.Errorf("got %q", got)
+	}
+	if got := caller.Package(TestPackage); got != "github.com/xandalm/go-testing/internal/caller_test." {
+		t.Errorf("got %q", got)
+	}
+}
+
+func locate(h *caller.Helpers) int {
+	h.Mark(0)
+	frame, _ := h.Frame(0)
+	return frame.Line
+}
+
+func TestHelpers(t *testing.T) {
+	var h caller.Helpers
+	line := locate(&h)
+	frame, _ := h.Frame(0)
+	if filepath.Base(frame.File) != "caller_test.go" || frame.Line != line+1 {
+		t.Errorf("helper should be skipped, got %s:%d after line %d", frame.File, frame.Line, line)
+	}
+}
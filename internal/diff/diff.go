@@ -0,0 +1,155 @@
+// Package diff shows the lines differing between two contents.
+package diff
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// contextLines is the number of unchanged lines shown around changes.
+	contextLines = 2
+	// maxDiffCells bounds the size of the table used to find the longest
+	// common subsequence of lines. Larger contents are shown as replaced
+	// as a whole.
+	maxDiffCells = 1 << 22
+)
+
+// Content returns the lines differing between got and want, which must
+// differ, each preceded by indent and marked "-" if only in want or "+" if
+// only in got. Binary contents are only told apart by their sizes.
+func Content(got, want []byte, indent string) string {
+	if isBinary(got) || isBinary(want) {
+		return fmt.Sprintf("%sbinary content differs: %d bytes, want %d bytes", indent, len(got), len(want))
+	}
+	var b strings.Builder
+	for _, line := range lineDiff(splitLines(want), splitLines(got)) {
+		if line[2:] == "" {
+			// no trailing spaces after the marker of empty lines
+			b.WriteString(strings.TrimRight(indent+line[:1], " \t"))
+			continue
+		}
+		b.WriteString(indent + line)
+	}
+	return b.String()
+}
+
+func isBinary(b []byte) bool {
+	return !utf8.Valid(b) || bytes.IndexByte(b, 0) >= 0
+}
+
+// splitLines splits s into lines, keeping a missing final newline visible.
+func splitLines(s []byte) []string {
+	if len(s) == 0 {
+		return nil
+	}
+	text := string(s)
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	for i, line := range lines {
+		if l, ok := strings.CutSuffix(line, "\n"); ok {
+			lines[i] = l
+		} else {
+			lines[i] = l + "\\ no newline at end"
+		}
+	}
+	return lines
+}
+
+// lineDiff returns the edit script turning a into b, cutting unchanged
+// lines away from the changes.
+func lineDiff(a, b []string) []string {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var ops []string
+	for _, line := range a[:prefix] {
+		ops = append(ops, "  "+line)
+	}
+	ops = append(ops, middleDiff(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, "  "+line)
+	}
+	return withContext(ops)
+}
+
+// middleDiff diffs a and b by their longest common subsequence.
+func middleDiff(a, b []string) []string {
+	var ops []string
+	if len(a)*len(b) > maxDiffCells {
+		for _, line := range a {
+			ops = append(ops, "- "+line)
+		}
+		for _, line := range b {
+			ops = append(ops, "+ "+line)
+		}
+		return ops
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:]
+	// and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, "  "+a[i])
+			i++
+			j++
+		case j == len(b) || i < len(a) && lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, "- "+a[i])
+			i++
+		default:
+			ops = append(ops, "+ "+b[j])
+			j++
+		}
+	}
+	return ops
+}
+
+// withContext keeps the unchanged lines at most contextLines away from a
+// change, replacing the others by "...".
+func withContext(ops []string) []string {
+	keep := make([]bool, len(ops))
+	for i, op := range ops {
+		if op[0] == ' ' {
+			continue
+		}
+		for j := max(0, i-contextLines); j <= min(len(ops)-1, i+contextLines); j++ {
+			keep[j] = true
+		}
+	}
+	var out []string
+	for i, op := range ops {
+		switch {
+		case keep[i]:
+			out = append(out, op)
+		case i == 0 || keep[i-1]:
+			out = append(out, "...")
+		}
+	}
+	return out
+}
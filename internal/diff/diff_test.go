@@ -0,0 +1,28 @@
+package diff_test
+
+import (
+	"testing"
+
+	"github.com/xandalm/go-testing/internal/diff"
+)
+
+func TestContent(t *testing.T) {
+	cases := []struct {
+		name      string
+		got, want string
+		diff      string
+	}{
+		{"changed", "a\nx\nc\n", "a\nb\nc\n", "|  a|- b|+ x|  c"},
+		{"context", "1\n2\n3\n4\n5\n6\nx\n", "1\n2\n3\n4\n5\n6\n7\n", "|...|  5|  6|- 7|+ x"},
+		{"no final newline", "a", "a\n", "|- a|+ a\\ no newline at end"},
+		{"empty line", "a\n\n", "a\n", "|  a|+"},
+		{"binary", "\x00", "abc", "|binary content differs: 1 bytes, want 3 bytes"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := diff.Content([]byte(c.got), []byte(c.want), "|"); got != c.diff {
+				t.Errorf("got %q, want %q", got, c.diff)
+			}
+		})
+	}
+}
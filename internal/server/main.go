@@ -0,0 +1,36 @@
+// Command server is the sample server the tests of ServerLauncher launch.
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+)
+
+// handler greets, with the greeting of the service at UPSTREAM_URL when
+// set.
+var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	upstream := os.Getenv("UPSTREAM_URL")
+	if upstream == "" {
+		fmt.Fprint(w, "Hi there")
+		return
+	}
+	res, err := http.Get(upstream + "/greeting")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer res.Body.Close()
+	io.Copy(w, res.Body)
+})
+
+func run(addr string) error {
+	log.Printf("listening on %s", addr)
+	return http.ListenAndServe(addr, handler)
+}
+
+func main() {
+	log.Fatal(run(":5000"))
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	tpkg "github.com/xandalm/go-testing"
+	"github.com/xandalm/go-testing/assert"
+	"github.com/xandalm/go-testing/assert/httpassert"
+	"github.com/xandalm/go-testing/assert/mockhttp"
+)
+
+func TestHandler(t *testing.T) {
+	t.Setenv("UPSTREAM_URL", "")
+	httpassert.HandlerReturns(t, handler, "GET", "/", "", http.StatusOK, httpassert.Equals("Hi there"))
+}
+
+func TestHandlerUpstream(t *testing.T) {
+	upstream := mockhttp.NewServer(t)
+	upstream.Expect("GET", "/greeting").Respond(http.StatusOK, "Hi from upstream")
+	t.Setenv("UPSTREAM_URL", upstream.URL)
+
+	httpassert.HandlerReturns(t, handler, "GET", "/", "", http.StatusOK, httpassert.Equals("Hi from upstream"))
+	upstream.Verify(t)
+}
+
+func TestRun(t *testing.T) {
+	var err error
+	records := tpkg.CaptureLog(t, func() {
+		err = run("invalid address")
+	})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, len(records), 1)
+	assert.Equal(t, records[0].Message, "listening on invalid address")
+}
@@ -0,0 +1,93 @@
+// Package txtar reads and writes txtar archives: a comment followed by
+// files, each introduced by a "-- name --" marker line.
+//
+//	comment
+//	-- hello.txt --
+//	Hello, world!
+//	-- dir/empty.txt --
+package txtar
+
+import (
+	"bytes"
+	"strings"
+)
+
+type Archive struct {
+	Comment []byte
+	Files   []File
+}
+
+type File struct {
+	Name string
+	Data []byte
+}
+
+var (
+	newlineMarker = []byte("\n-- ")
+	marker        = []byte("-- ")
+	markerEnd     = []byte(" --")
+)
+
+// Format returns the archive as text. Data not ending in a newline get
+// one, so the next marker starts a line.
+func Format(a *Archive) []byte {
+	var b bytes.Buffer
+	b.Write(fixNewline(a.Comment))
+	for _, f := range a.Files {
+		b.WriteString("-- " + f.Name + " --\n")
+		b.Write(fixNewline(f.Data))
+	}
+	return b.Bytes()
+}
+
+// Parse parses the archive in data. Any text is a valid archive, lines
+// before the first marker being the comment.
+func Parse(data []byte) *Archive {
+	a := new(Archive)
+	var name string
+	a.Comment, name, data = findFile(data)
+	for name != "" {
+		f := File{Name: name}
+		f.Data, name, data = findFile(data)
+		a.Files = append(a.Files, f)
+	}
+	return a
+}
+
+// findFile returns the text before the next marker line, the file name of
+// the marker and the text after it.
+func findFile(data []byte) (before []byte, name string, after []byte) {
+	var i int
+	for {
+		if name, after = isMarker(data[i:]); name != "" {
+			return data[:i], name, after
+		}
+		j := bytes.Index(data[i:], newlineMarker)
+		if j < 0 {
+			return fixNewline(data), "", nil
+		}
+		i += j + 1
+	}
+}
+
+// isMarker returns the file name of the marker line data starts with, and
+// the text after that line.
+func isMarker(data []byte) (name string, after []byte) {
+	if !bytes.HasPrefix(data, marker) {
+		return "", nil
+	}
+	line, after, _ := bytes.Cut(data, []byte("\n"))
+	line = bytes.TrimSuffix(line, []byte("\r"))
+	if !bytes.HasSuffix(line, markerEnd) || len(line) < len(marker)+len(markerEnd) {
+		return "", nil
+	}
+	name = strings.TrimSpace(string(line[len(marker) : len(line)-len(markerEnd)]))
+	return name, after
+}
+
+func fixNewline(data []byte) []byte {
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		data = append(data[:len(data):len(data)], '\n')
+	}
+	return data
+}
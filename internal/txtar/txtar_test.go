@@ -0,0 +1,39 @@
+package txtar_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/xandalm/go-testing/internal/txtar"
+)
+
+func TestParse(t *testing.T) {
+	data := "comment\n-- a.txt --\nhello\n--not a marker--\n-- dir/b.txt --\n-- c.txt --\nno newline"
+	want := &txtar.Archive{
+		Comment: []byte("comment\n"),
+		Files: []txtar.File{
+			{"a.txt", []byte("hello\n--not a marker--\n")},
+			{"dir/b.txt", []byte{}},
+			{"c.txt", []byte("no newline\n")},
+		},
+	}
+	got := txtar.Parse([]byte(data))
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	formatted := string(txtar.Format(got))
+	if formatted != data+"\n" {
+		t.Errorf("formatted as %q", formatted)
+	}
+	if got := txtar.Parse([]byte(formatted)); !reflect.DeepEqual(got, want) {
+		t.Errorf("reparsed as %q", got)
+	}
+}
+
+func TestParseNoFiles(t *testing.T) {
+	got := txtar.Parse([]byte("just a comment"))
+	if string(got.Comment) != "just a comment\n" || len(got.Files) != 0 {
+		t.Errorf("got %q", got)
+	}
+}
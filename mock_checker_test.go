@@ -0,0 +1,53 @@
+// Code generated by github.com/xandalm/go-testing/cmd; DO NOT EDIT.
+
+package testing_test
+
+import (
+	"github.com/xandalm/go-testing/assert/mock"
+)
+
+// MockAvailabilityChecker is a mock of AvailabilityChecker.
+type MockAvailabilityChecker struct {
+	*mock.Expectations
+}
+
+// NewMockAvailabilityChecker returns a mock of AvailabilityChecker expecting no calls.
+func NewMockAvailabilityChecker() *MockAvailabilityChecker {
+	return &MockAvailabilityChecker{mock.NewExpectations("AvailabilityChecker")}
+}
+
+// MockAvailabilityCheckerPingCall is an expected call of Ping.
+type MockAvailabilityCheckerPingCall struct {
+	*mock.Expected
+}
+
+// ExpectPing expects a call of Ping with the given arguments.
+func (m *MockAvailabilityChecker) ExpectPing() *MockAvailabilityCheckerPingCall {
+	return &MockAvailabilityCheckerPingCall{m.Expectations.Expect("Ping")}
+}
+
+// Return sets the results of the call.
+func (c *MockAvailabilityCheckerPingCall) Return(r0 error) *MockAvailabilityCheckerPingCall {
+	c.Expected.Return(r0)
+	return c
+}
+
+// Times sets the number of calls expected, or any number if n is negative.
+func (c *MockAvailabilityCheckerPingCall) Times(n int) *MockAvailabilityCheckerPingCall {
+	c.Expected.Times(n)
+	return c
+}
+
+// AnyArgs makes calls with any arguments match.
+func (c *MockAvailabilityCheckerPingCall) AnyArgs() *MockAvailabilityCheckerPingCall {
+	c.Expected.AnyArgs()
+	return c
+}
+
+func (m *MockAvailabilityChecker) Ping() (r0 error) {
+	results := m.Expectations.Call("Ping")
+	if results != nil {
+		r0, _ = results[0].(error)
+	}
+	return
+}
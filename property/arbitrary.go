@@ -0,0 +1,131 @@
+package property
+
+import (
+	"math"
+	"reflect"
+)
+
+const (
+	// maxArbitraryDepth is the depth past which Arbitrary generates zero
+	// values, so recursive types are generated in finite time.
+	maxArbitraryDepth = 4
+	// maxArbitraryLen is the maximum length of the strings, slices and
+	// maps generated by Arbitrary.
+	maxArbitraryLen = 8
+	// maxArbitraryInt bounds the magnitude of the numbers generated by
+	// Arbitrary.
+	maxArbitraryInt = 1000
+)
+
+const printable = " !\"#$%&'()*+,-./0123456789:;<=>?@ABCDEFGHIJKLMNOPQRSTUVWXYZ[\\]^_`abcdefghijklmnopqrstuvwxyz{|}~"
+
+// Arbitrary generates values of any type T by reflection: small numbers,
+// printable strings, short slices and maps, nil or set pointers and
+// structs with their exported fields generated likewise. Interfaces,
+// functions and channels are left nil, as are unexported fields.
+func Arbitrary[T any]() Gen[T] {
+	g := arbitrary(reflect.TypeFor[T](), 0)
+	return Map(g, func(v reflect.Value) T {
+		// nil when T is an interface
+		x, _ := v.Interface().(T)
+		return x
+	})
+}
+
+func arbitrary(typ reflect.Type, depth int) Gen[reflect.Value] {
+	zero := Just(reflect.Zero(typ))
+	if depth > maxArbitraryDepth {
+		return zero
+	}
+	convert := func(v any) reflect.Value {
+		return reflect.ValueOf(v).Convert(typ)
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		return Map(Bool(), func(b bool) reflect.Value { return convert(b) })
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		limit := maxArbitraryInt
+		if typ.Bits() == 8 {
+			limit = math.MaxInt8
+		}
+		return Map(Int(-limit, limit), func(n int) reflect.Value { return convert(n) })
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		limit := maxArbitraryInt
+		if typ.Bits() == 8 {
+			limit = math.MaxUint8
+		}
+		return Map(Int(0, limit), func(n int) reflect.Value { return convert(uint64(n)) })
+	case reflect.Float32, reflect.Float64:
+		return Map(Float64(-maxArbitraryInt, maxArbitraryInt), func(f float64) reflect.Value { return convert(f) })
+	case reflect.Complex64, reflect.Complex128:
+		parts := SliceOf(Float64(-maxArbitraryInt, maxArbitraryInt), 2, 2)
+		return Map(parts, func(p []float64) reflect.Value { return convert(complex(p[0], p[1])) })
+	case reflect.String:
+		return Map(String(printable, 0, maxArbitraryLen), func(s string) reflect.Value { return convert(s) })
+	case reflect.Pointer:
+		set := Map(arbitrary(typ.Elem(), depth+1), func(v reflect.Value) reflect.Value {
+			p := reflect.New(typ.Elem())
+			p.Elem().Set(v)
+			return p
+		})
+		return OneOf(zero, set)
+	case reflect.Slice:
+		return Map(SliceOf(arbitrary(typ.Elem(), depth+1), 0, maxArbitraryLen), func(elems []reflect.Value) reflect.Value {
+			s := reflect.MakeSlice(typ, len(elems), len(elems))
+			for i, e := range elems {
+				s.Index(i).Set(e)
+			}
+			return s
+		})
+	case reflect.Array:
+		return Map(SliceOf(arbitrary(typ.Elem(), depth+1), typ.Len(), typ.Len()), func(elems []reflect.Value) reflect.Value {
+			a := reflect.New(typ).Elem()
+			for i, e := range elems {
+				a.Index(i).Set(e)
+			}
+			return a
+		})
+	case reflect.Map:
+		return Map(SliceOf(pair(typ.Key(), typ.Elem(), depth+1), 0, maxArbitraryLen), func(kvs [][2]reflect.Value) reflect.Value {
+			m := reflect.MakeMapWithSize(typ, len(kvs))
+			for _, kv := range kvs {
+				m.SetMapIndex(kv[0], kv[1])
+			}
+			return m
+		})
+	case reflect.Struct:
+		return structOf(typ, depth)
+	default:
+		return zero
+	}
+}
+
+// pair generates a key and an element of a map.
+func pair(key, elem reflect.Type, depth int) Gen[[2]reflect.Value] {
+	return Bind(arbitrary(key, depth), func(k reflect.Value) Gen[[2]reflect.Value] {
+		return Map(arbitrary(elem, depth), func(e reflect.Value) [2]reflect.Value {
+			return [2]reflect.Value{k, e}
+		})
+	})
+}
+
+// structOf generates the exported fields of the struct type, shrinking
+// each field in turn.
+func structOf(typ reflect.Type, depth int) Gen[reflect.Value] {
+	var fields []int
+	var gens []Gen[reflect.Value]
+	for i := range typ.NumField() {
+		if f := typ.Field(i); f.IsExported() {
+			fields = append(fields, i)
+			gens = append(gens, arbitrary(f.Type, depth+1))
+		}
+	}
+	return Map(sequence(gens), func(values []reflect.Value) reflect.Value {
+		v := reflect.New(typ).Elem()
+		for i, fv := range values {
+			v.Field(fields[i]).Set(fv)
+		}
+		return v
+	})
+}
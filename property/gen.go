@@ -0,0 +1,138 @@
+package property
+
+import "math/rand/v2"
+
+// maxFilterTries is the number of values a filtered generator draws before
+// giving up.
+const maxFilterTries = 1000
+
+// tree is a generated value with the simpler values it can shrink to, in
+// the order they are tried.
+type tree[T any] struct {
+	value  T
+	shrink func() []tree[T]
+}
+
+func leaf[T any](v T) tree[T] {
+	return tree[T]{value: v}
+}
+
+func (t tree[T]) children() []tree[T] {
+	if t.shrink == nil {
+		return nil
+	}
+	return t.shrink()
+}
+
+func mapTree[A, B any](t tree[A], f func(A) B) tree[B] {
+	return tree[B]{f(t.value), func() []tree[B] {
+		children := t.children()
+		mapped := make([]tree[B], len(children))
+		for i, c := range children {
+			mapped[i] = mapTree(c, f)
+		}
+		return mapped
+	}}
+}
+
+func filterTree[T any](t tree[T], keep func(T) bool) tree[T] {
+	return tree[T]{t.value, func() []tree[T] {
+		var kept []tree[T]
+		for _, c := range t.children() {
+			if keep(c.value) {
+				kept = append(kept, filterTree(c, keep))
+			}
+		}
+		return kept
+	}}
+}
+
+// Gen generates random values of type T, knowing how to shrink them to
+// simpler values.
+type Gen[T any] struct {
+	generate func(r *rand.Rand) tree[T]
+}
+
+func (g Gen[T]) tree(r *rand.Rand) tree[T] {
+	if g.generate == nil {
+		panic("property: zero generator")
+	}
+	return g.generate(r)
+}
+
+// Sample returns a value generated from seed.
+func (g Gen[T]) Sample(seed uint64) T {
+	return g.tree(newRand(seed)).value
+}
+
+func newRand(seed uint64) *rand.Rand {
+	return rand.New(rand.NewPCG(seed, seed))
+}
+
+// Map generates the results of f for the values generated by g.
+func Map[A, B any](g Gen[A], f func(A) B) Gen[B] {
+	return Gen[B]{func(r *rand.Rand) tree[B] {
+		return mapTree(g.tree(r), f)
+	}}
+}
+
+// Filter generates the values generated by g for which keep returns true.
+// It panics if keep rejects too many values in a row.
+func Filter[T any](g Gen[T], keep func(T) bool) Gen[T] {
+	return Gen[T]{func(r *rand.Rand) tree[T] {
+		for range maxFilterTries {
+			if t := g.tree(r); keep(t.value) {
+				return filterTree(t, keep)
+			}
+		}
+		panic("property: filter rejected too many values")
+	}}
+}
+
+// Bind generates the values generated by the generator f returns for each
+// value generated by g.
+func Bind[A, B any](g Gen[A], f func(A) Gen[B]) Gen[B] {
+	return Gen[B]{func(r *rand.Rand) tree[B] {
+		return bindTree(g.tree(r), f, r.Uint64())
+	}}
+}
+
+// bindTree shrinks the value of ta first, generating again from the same
+// seed with the simpler values, then the value generated from it.
+func bindTree[A, B any](ta tree[A], f func(A) Gen[B], seed uint64) tree[B] {
+	tb := f(ta.value).tree(newRand(seed))
+	return tree[B]{tb.value, func() []tree[B] {
+		var children []tree[B]
+		for _, c := range ta.children() {
+			children = append(children, bindTree(c, f, seed))
+		}
+		return append(children, tb.children()...)
+	}}
+}
+
+// Just always generates v.
+func Just[T any](v T) Gen[T] {
+	return Gen[T]{func(*rand.Rand) tree[T] {
+		return leaf(v)
+	}}
+}
+
+// Elements generates one of values, shrinking towards the first.
+func Elements[T any](values ...T) Gen[T] {
+	if len(values) == 0 {
+		panic("property: no elements")
+	}
+	return Map(Int(0, len(values)-1), func(i int) T {
+		return values[i]
+	})
+}
+
+// OneOf generates the values of one of gens, shrinking towards the first.
+func OneOf[T any](gens ...Gen[T]) Gen[T] {
+	if len(gens) == 0 {
+		panic("property: no generators")
+	}
+	return Bind(Int(0, len(gens)-1), func(i int) Gen[T] {
+		return gens[i]
+	})
+}
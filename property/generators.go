@@ -0,0 +1,169 @@
+package property
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+)
+
+// Int generates ints in [min, max], shrinking towards the value closest to
+// zero.
+func Int(min, max int) Gen[int] {
+	if min > max {
+		panic("property: invalid int range")
+	}
+	origin := 0
+	if min > 0 {
+		origin = min
+	} else if max < 0 {
+		origin = max
+	}
+	return Gen[int]{func(r *rand.Rand) tree[int] {
+		n := uint64(max) - uint64(min) + 1
+		var u uint64
+		if n == 0 {
+			// the whole int range
+			u = r.Uint64()
+		} else {
+			u = r.Uint64N(n)
+		}
+		return intTree(min+int(u), origin)
+	}}
+}
+
+// intTree shrinks v to origin first, then to values halving the distance
+// to it.
+func intTree(v, origin int) tree[int] {
+	return tree[int]{v, func() []tree[int] {
+		var children []tree[int]
+		for d := v - origin; d != 0; d /= 2 {
+			children = append(children, intTree(v-d, origin))
+		}
+		return children
+	}}
+}
+
+// Bool generates booleans, shrinking towards false.
+func Bool() Gen[bool] {
+	return Elements(false, true)
+}
+
+// Float64 generates float64 values in [min, max], shrinking towards the
+// value closest to zero and towards integers.
+func Float64(min, max float64) Gen[float64] {
+	if !(min <= max) || math.IsInf(max-min, 0) {
+		panic("property: invalid float range")
+	}
+	origin := math.Max(min, math.Min(max, 0))
+	return Gen[float64]{func(r *rand.Rand) tree[float64] {
+		return floatTree(min+r.Float64()*(max-min), origin, 0)
+	}}
+}
+
+// maxFloatShrinks bounds the depth of the shrinking of floats, which
+// could halve distances for long.
+const maxFloatShrinks = 32
+
+func floatTree(v, origin float64, depth int) tree[float64] {
+	return tree[float64]{v, func() []tree[float64] {
+		if v == origin || depth == maxFloatShrinks {
+			return nil
+		}
+		children := []tree[float64]{leaf(origin)}
+		if t := math.Trunc(v); t != v && t != origin {
+			children = append(children, floatTree(t, origin, depth+1))
+		}
+		return append(children, floatTree(v-(v-origin)/2, origin, depth+1))
+	}}
+}
+
+// String generates strings of runes from alphabet with lengths, in runes,
+// in [minLen, maxLen]. It shrinks to shorter strings and towards the first
+// rune of alphabet.
+func String(alphabet string, minLen, maxLen int) Gen[string] {
+	runes := []rune(alphabet)
+	if len(runes) == 0 {
+		panic("property: empty alphabet")
+	}
+	return Map(SliceOf(Elements(runes...), minLen, maxLen), func(rs []rune) string {
+		return string(rs)
+	})
+}
+
+// SliceOf generates slices of the values of g with lengths in [minLen,
+// maxLen]. It shrinks to shorter slices and to slices of simpler values.
+func SliceOf[T any](g Gen[T], minLen, maxLen int) Gen[[]T] {
+	if minLen < 0 || minLen > maxLen {
+		panic("property: invalid length range")
+	}
+	return Gen[[]T]{func(r *rand.Rand) tree[[]T] {
+		elems := make([]tree[T], minLen+r.IntN(maxLen-minLen+1))
+		for i := range elems {
+			elems[i] = g.tree(r)
+		}
+		return sliceTree(elems, minLen)
+	}}
+}
+
+// sequence generates a value of each of gens, shrinking each in turn.
+func sequence[T any](gens []Gen[T]) Gen[[]T] {
+	return Gen[[]T]{func(r *rand.Rand) tree[[]T] {
+		elems := make([]tree[T], len(gens))
+		for i, g := range gens {
+			elems[i] = g.tree(r)
+		}
+		return sliceTree(elems, len(elems))
+	}}
+}
+
+// sliceTree shrinks by removing chunks of elements, from the largest down
+// to single elements, and then by shrinking each element.
+func sliceTree[T any](elems []tree[T], minLen int) tree[[]T] {
+	value := make([]T, len(elems))
+	for i, e := range elems {
+		value[i] = e.value
+	}
+	return tree[[]T]{value, func() []tree[[]T] {
+		var children []tree[[]T]
+		for chunk := len(elems) - minLen; chunk > 0; chunk /= 2 {
+			for start := 0; start+chunk <= len(elems); start += chunk {
+				rest := slices.Delete(slices.Clone(elems), start, start+chunk)
+				children = append(children, sliceTree(rest, minLen))
+			}
+		}
+		for i, e := range elems {
+			for _, c := range e.children() {
+				shrunk := slices.Clone(elems)
+				shrunk[i] = c
+				children = append(children, sliceTree(shrunk, minLen))
+			}
+		}
+		return children
+	}}
+}
+
+// MapOf generates maps with keys and values generated by keys and values,
+// with lengths in [minLen, maxLen]. Keys generated more than once are kept
+// once, so the key generator must be able to generate minLen distinct
+// keys.
+func MapOf[K comparable, V any](keys Gen[K], values Gen[V], minLen, maxLen int) Gen[map[K]V] {
+	type entry struct {
+		k K
+		v V
+	}
+	entries := Bind(keys, func(k K) Gen[entry] {
+		return Map(values, func(v V) entry {
+			return entry{k, v}
+		})
+	})
+	m := Map(SliceOf(entries, minLen, maxLen), func(es []entry) map[K]V {
+		m := make(map[K]V, len(es))
+		for _, e := range es {
+			m[e.k] = e.v
+		}
+		return m
+	})
+	return Filter(m, func(m map[K]V) bool {
+		return len(m) >= minLen
+	})
+}
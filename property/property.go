@@ -0,0 +1,164 @@
+// Package property provides property-based testing: checking a property
+// holds for many random values, and reporting the simplest value found
+// breaking it.
+package property
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/xandalm/go-testing/assert"
+	"github.com/xandalm/go-testing/assert/asserttest"
+)
+
+// SeedEnv is the environment variable setting the seed of the random
+// values, to reproduce failures. When unset, a random seed is used.
+const SeedEnv = "PROPERTY_SEED"
+
+// Option configures ForAll.
+type Option func(*config)
+
+type config struct {
+	runs       int
+	maxShrinks int
+	seed       uint64
+	seeded     bool
+}
+
+// Runs sets the number of values the property is checked with, 100 by
+// default.
+func Runs(n int) Option {
+	if n <= 0 {
+		panic("property: runs must be positive")
+	}
+	return func(c *config) {
+		c.runs = n
+	}
+}
+
+// MaxShrinks sets the number of shrinking steps taken at most, 1000 by
+// default.
+func MaxShrinks(n int) Option {
+	if n < 0 {
+		panic("property: max shrinks must not be negative")
+	}
+	return func(c *config) {
+		c.maxShrinks = n
+	}
+}
+
+// Seed sets the seed of the random values, taking precedence over
+// SeedEnv.
+func Seed(seed uint64) Option {
+	return func(c *config) {
+		c.seed, c.seeded = seed, true
+	}
+}
+
+func newConfig(t testing.TB, opts []Option) config {
+	t.Helper()
+
+	c := config{runs: 100, maxShrinks: 1000}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	if c.seeded {
+		return c
+	}
+	if env := os.Getenv(SeedEnv); env != "" {
+		seed, err := strconv.ParseUint(env, 10, 64)
+		if err != nil {
+			t.Fatalf("property: invalid %s: %v", SeedEnv, err)
+		}
+		c.seed = seed
+	} else {
+		c.seed = rand.Uint64()
+	}
+	return c
+}
+
+// result is the outcome of checking the property with a value.
+type result struct {
+	failed  bool
+	skipped bool
+	calls   []asserttest.Call
+}
+
+// check calls prop with v and a testing.TB recording its failures, so
+// properties can use the assertions of the assert package. A panic fails
+// the property and calling Skip discards v.
+func check[T any](t testing.TB, prop func(t testing.TB, v T), v T) result {
+	r := asserttest.NewTB(t)
+	r.Run(func(t testing.TB) {
+		defer func() {
+			if p := recover(); p != nil {
+				t.Errorf("panic: %v", p)
+			}
+		}()
+		prop(t, v)
+	})
+	return result{r.Failed(), r.Skipped(), r.Calls()}
+}
+
+// ForAll checks prop holds for values generated by gen, failing t with the
+// simplest counterexample found by shrinking the first failing value, and
+// the seed reproducing it.
+func ForAll[T any](t testing.TB, gen Gen[T], prop func(t testing.TB, v T), opts ...Option) {
+	t.Helper()
+
+	if prop == nil {
+		panic("property: nil property")
+	}
+	c := newConfig(t, opts)
+	r := newRand(c.seed)
+	discarded := 0
+	for run := 1; run <= c.runs; run++ {
+		v := gen.tree(r)
+		res := check(t, prop, v.value)
+		if res.skipped {
+			if discarded++; discarded > 10*c.runs {
+				t.Fatalf("property: gave up after discarding %d values (seed %d)", discarded, c.seed)
+			}
+			run--
+			continue
+		}
+		if !res.failed {
+			continue
+		}
+
+		shrunk, res, steps := shrink(t, prop, v, res, c.maxShrinks)
+		var b strings.Builder
+		fmt.Fprintf(&b, "property failed after %d runs, seed %d (rerun with %s=%d)", run, c.seed, SeedEnv, c.seed)
+		fmt.Fprintf(&b, "\ncounterexample: %s", assert.Format(shrunk))
+		if steps > 0 {
+			fmt.Fprintf(&b, "\nshrunk from %s in %d steps", assert.Format(v.value), steps)
+		}
+		for _, call := range res.calls {
+			b.WriteString("\n\t" + strings.ReplaceAll(call.String(), "\n", "\n\t"))
+		}
+		t.Fatal(b.String())
+	}
+}
+
+// shrink walks down the shrink tree of the failing value v, moving to the
+// first simpler value still failing, until there are none or max steps
+// were taken.
+func shrink[T any](t testing.TB, prop func(t testing.TB, v T), v tree[T], res result, max int) (T, result, int) {
+	steps := 0
+outer:
+	for steps < max {
+		for _, c := range v.children() {
+			if r := check(t, prop, c.value); r.failed {
+				v, res = c, r
+				steps++
+				continue outer
+			}
+		}
+		break
+	}
+	return v.value, res, steps
+}
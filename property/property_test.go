@@ -0,0 +1,185 @@
+package property_test
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/xandalm/go-testing/assert"
+	"github.com/xandalm/go-testing/assert/asserttest"
+	"github.com/xandalm/go-testing/property"
+)
+
+func failure(t *testing.T, fn func(t testing.TB)) string {
+	t.Helper()
+	msgs := asserttest.ExpectFailure(t, fn).Messages("Fatal")
+	if len(msgs) != 1 {
+		t.Fatalf("expected one failure, got %q", msgs)
+	}
+	return msgs[0]
+}
+
+func TestForAll(t *testing.T) {
+	asserttest.ExpectSuccess(t, func(t testing.TB) {
+		property.ForAll(t, property.SliceOf(property.Int(-10, 10), 0, 20), func(t testing.TB, s []int) {
+			r := slices.Clone(s)
+			slices.Reverse(r)
+			slices.Reverse(r)
+			assert.Equal(t, r, s)
+		})
+	})
+
+	t.Run("shrinks ints", func(t *testing.T) {
+		got := failure(t, func(t testing.TB) {
+			property.ForAll(t, property.Int(0, 1000), func(t testing.TB, n int) {
+				assert.Smaller(t, n, 50)
+			}, property.Seed(1))
+		})
+		for _, want := range []string{
+			"property failed after ",
+			"seed 1 (rerun with PROPERTY_SEED=1)",
+			"\ncounterexample: 50\nshrunk from ",
+			"Fatal: 50 is actually greater than 50",
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("message should contain %q, got %q", want, got)
+			}
+		}
+	})
+
+	t.Run("shrinks slices", func(t *testing.T) {
+		got := failure(t, func(t testing.TB) {
+			property.ForAll(t, property.SliceOf(property.Int(-100, 100), 0, 10), func(t testing.TB, s []int) {
+				if len(s) >= 3 {
+					t.Errorf("too long")
+				}
+			}, property.Seed(2))
+		})
+		if !strings.Contains(got, "\ncounterexample: []int{0, 0, 0}\n") {
+			t.Errorf("unexpected message %q", got)
+		}
+	})
+
+	t.Run("seed from environment", func(t *testing.T) {
+		t.Setenv(property.SeedEnv, "42")
+		got := failure(t, func(t testing.TB) {
+			property.ForAll(t, property.Int(1, 10), func(t testing.TB, n int) {
+				t.Fatal("always fails")
+			})
+		})
+		if !strings.HasPrefix(got, "property failed after 1 runs, seed 42 (rerun with PROPERTY_SEED=42)\ncounterexample: 1\n") {
+			t.Errorf("unexpected message %q", got)
+		}
+	})
+
+	t.Run("panics fail", func(t *testing.T) {
+		got := failure(t, func(t testing.TB) {
+			property.ForAll(t, property.Int(0, 10), func(t testing.TB, n int) {
+				_ = []int{}[n]
+			}, property.Seed(3))
+		})
+		if !strings.Contains(got, "counterexample: 0") || !strings.Contains(got, "panic: runtime error: index out of range") {
+			t.Errorf("unexpected message %q", got)
+		}
+	})
+
+	t.Run("skip discards", func(t *testing.T) {
+		checked := 0
+		asserttest.ExpectSuccess(t, func(t testing.TB) {
+			property.ForAll(t, property.Int(0, 100), func(t testing.TB, n int) {
+				if n%2 != 0 {
+					t.SkipNow()
+				}
+				checked++
+				assert.Equal(t, n%2, 0)
+			}, property.Runs(20))
+		})
+		assert.Equal(t, checked, 20)
+	})
+}
+
+func TestGenerators(t *testing.T) {
+	type pair struct {
+		N int
+		S string
+	}
+	gens := map[string]property.Gen[pair]{
+		"Map": property.Map(property.Int(0, 9), func(n int) pair {
+			return pair{n, strings.Repeat("x", n)}
+		}),
+		"Bind": property.Bind(property.Int(1, 5), func(n int) property.Gen[pair] {
+			return property.Map(property.String("ab", n, n), func(s string) pair {
+				return pair{n, s}
+			})
+		}),
+		"Filter": property.Filter(property.Arbitrary[pair](), func(p pair) bool {
+			return p.N > 0
+		}),
+		"OneOf": property.OneOf(property.Just(pair{1, "one"}), property.Elements(pair{2, "two"}, pair{3, "three"})),
+	}
+	valid := map[string]func(p pair) bool{
+		"Map":    func(p pair) bool { return len(p.S) == p.N },
+		"Bind":   func(p pair) bool { return utf8.RuneCountInString(p.S) == p.N && strings.Trim(p.S, "ab") == "" },
+		"Filter": func(p pair) bool { return p.N > 0 },
+		"OneOf":  func(p pair) bool { return p.N == 1 || p.N == 2 || p.N == 3 },
+	}
+	for name, g := range gens {
+		t.Run(name, func(t *testing.T) {
+			for seed := range uint64(200) {
+				if p := g.Sample(seed); !valid[name](p) {
+					t.Fatalf("invalid value %+v from seed %d", p, seed)
+				}
+			}
+			assert.Equal(t, g.Sample(7), g.Sample(7))
+		})
+	}
+
+	t.Run("MapOf", func(t *testing.T) {
+		g := property.MapOf(property.Int(0, 9), property.Bool(), 2, 5)
+		for seed := range uint64(200) {
+			if m := g.Sample(seed); len(m) < 2 || len(m) > 5 {
+				t.Fatalf("invalid length %d from seed %d", len(m), seed)
+			}
+		}
+	})
+}
+
+func TestArbitrary(t *testing.T) {
+	type inner struct {
+		Tags  []string
+		Score float64
+	}
+	type record struct {
+		ID     int8
+		Name   string
+		Inner  *inner
+		Attrs  map[string]uint16
+		Pair   [2]bool
+		hidden int
+	}
+
+	got := failure(t, func(t testing.TB) {
+		property.ForAll(t, property.Arbitrary[record](), func(t testing.TB, r record) {
+			if r.Inner != nil && len(r.Inner.Tags) > 1 {
+				t.Error("too many tags")
+			}
+		}, property.Seed(4))
+	})
+	want := `Inner: &property_test.inner{Tags: []string{"", ""}, Score: 0},`
+	if !strings.Contains(got, want) {
+		t.Errorf("message should contain %q, got %q", want, got)
+	}
+
+	g := property.Arbitrary[record]()
+	for seed := range uint64(100) {
+		r := g.Sample(seed)
+		assert.Equal(t, r.hidden, 0)
+		if r.Inner != nil {
+			assert.True(t, len(r.Inner.Tags) <= 8)
+		}
+	}
+	assert.Nil(t, property.Arbitrary[any]().Sample(1))
+	assert.Nil(t, property.Arbitrary[fmt.Stringer]().Sample(1))
+}
@@ -0,0 +1,170 @@
+// Package script tests command line programs with scripts, in the manner
+// of testscript. A script is a txtar archive whose comment holds the
+// commands, one per line, and whose files are written to the work
+// directory the script runs in:
+//
+//	# greets the user
+//	env NAME=gopher
+//	exec hello $NAME
+//	stdout '^hello, gopher$'
+//	! stderr .
+//	cmp stdout want.txt
+//
+//	-- want.txt --
+//	hello, gopher
+//
+// A command preceded by "!" is expected to fail. The commands are:
+//
+//	exec program [args...] [&]  run program, in the background with a final &
+//	stdin file                  give file as the input of the next exec
+//	stdout regexp               match the standard output of the last exec
+//	stderr regexp               match the standard error of the last exec
+//	cmp file1 file2             compare files, stdout and stderr included
+//	env key=value...            set environment variables
+//	cd dir                      change the current directory
+//	wait                        wait for the background programs
+//	kill [-signal]              signal the background programs, KILL by default
+//
+// Arguments are split at spaces, except within single quotes, where two
+// quotes make one, and $NAME or ${NAME} are replaced by environment
+// variables.
+// $WORK is the work directory.
+//
+// The programs under test run in-process when registered by RunMain, or
+// are built by the Program option.
+package script
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tpkg "github.com/xandalm/go-testing"
+	"github.com/xandalm/go-testing/assert/fsassert"
+	"github.com/xandalm/go-testing/internal/txtar"
+)
+
+// programEnv names the program the test binary runs as, instead of the
+// tests.
+const programEnv = "SCRIPT_PROGRAM"
+
+// programs are the programs registered by RunMain.
+var programs map[string]func() int
+
+// RunMain runs the tests of m, making programs available to the scripts
+// under their names. It's meant to be called from TestMain:
+//
+//	func TestMain(m *testing.M) {
+//		os.Exit(script.RunMain(m, map[string]func() int{
+//			"hello": hello.Main,
+//		}))
+//	}
+//
+// The programs run in the test binary, started again as the program.
+// They return their exit code.
+func RunMain(m *testing.M, progs map[string]func() int) int {
+	if name := os.Getenv(programEnv); name != "" {
+		main, ok := progs[name]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "script: unknown program %q\n", name)
+			return 2
+		}
+		os.Unsetenv(programEnv)
+		os.Args[0] = name
+		return main()
+	}
+	programs = progs
+	return m.Run()
+}
+
+// Option configures the running of scripts.
+type Option func(*config)
+
+type config struct {
+	builds []build
+	// built are the paths of the built programs, by name.
+	built map[string]string
+}
+
+type build struct {
+	name, wd, src string
+}
+
+// Program makes the Go program of src, a file or a package directory
+// relative to wd, available to the scripts as name. It's built once
+// before the scripts run, as by ServerLauncher.
+func Program(name, wd, src string) Option {
+	return func(c *config) {
+		c.builds = append(c.builds, build{name, wd, src})
+	}
+}
+
+func newConfig(t testing.TB, opts []Option) *config {
+	t.Helper()
+
+	c := &config{built: map[string]string{}}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if len(c.builds) == 0 {
+		return c
+	}
+	bin := t.TempDir()
+	for _, b := range c.builds {
+		exe := filepath.Join(bin, b.name)
+		if err := tpkg.Build(context.Background(), b.wd, b.src, exe); err != nil {
+			t.Fatalf("script: cannot build %s, %v", b.name, err)
+		}
+		c.built[b.name] = exe
+	}
+	return c
+}
+
+// Run runs each script of dir, files named *.txt, as a parallel subtest
+// named after the file.
+func Run(t *testing.T, dir string, opts ...Option) {
+	t.Helper()
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.txt"))
+	if err != nil {
+		t.Fatalf("script: %v", err)
+	}
+	if len(files) == 0 {
+		t.Fatalf("script: no scripts in %s", dir)
+	}
+	c := newConfig(t, opts)
+	for _, file := range files {
+		t.Run(strings.TrimSuffix(filepath.Base(file), ".txt"), func(t *testing.T) {
+			t.Parallel()
+			run(t, c, file)
+		})
+	}
+}
+
+// RunFile runs the script of file.
+func RunFile(t testing.TB, file string, opts ...Option) {
+	t.Helper()
+
+	run(t, newConfig(t, opts), file)
+}
+
+func run(t testing.TB, c *config, file string) {
+	t.Helper()
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("script: %v", err)
+	}
+	work := fsassert.Fixture(t, string(data))
+	s := newState(c, work)
+	defer s.stop()
+
+	for i, line := range strings.Split(string(txtar.Parse(data).Comment), "\n") {
+		if err := s.exec(line); err != nil {
+			t.Fatalf("%s\n%s:%d: %v", s.log.String(), file, i+1, err)
+		}
+	}
+}
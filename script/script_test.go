@@ -0,0 +1,103 @@
+package script_test
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xandalm/go-testing/assert/asserttest"
+	"github.com/xandalm/go-testing/script"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(script.RunMain(m, map[string]func() int{
+		"hello": hello,
+		"upper": upper,
+		"exit":  exit,
+		"serve": serve,
+	}))
+}
+
+func hello() int {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, "usage: hello name")
+		return 2
+	}
+	fmt.Printf("hello, %s\n", strings.Join(os.Args[1:], " "))
+	return 0
+}
+
+func upper() int {
+	b, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return 1
+	}
+	fmt.Print(strings.ToUpper(string(b)))
+	return 0
+}
+
+func exit() int {
+	code, _ := strconv.Atoi(os.Args[1])
+	fmt.Fprintf(os.Stderr, "exiting with %d\n", code)
+	return code
+}
+
+// serve runs until interrupted.
+func serve() int {
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	select {
+	case <-interrupt:
+		fmt.Println("interrupted")
+		return 0
+	case <-time.After(time.Minute):
+		return 1
+	}
+}
+
+func TestScripts(t *testing.T) {
+	script.Run(t, "testdata", script.Program("greet", "testdata/greet", "."))
+}
+
+func TestFailures(t *testing.T) {
+	cases := []struct {
+		file string
+		want []string
+	}{
+		{"exit.txt", []string{
+			"> exec exit 3\n[stderr]\nexiting with 3\n",
+			"testdata/fail/exit.txt:2: unexpected command failure: exit status 3",
+		}},
+		{"stdout.txt", []string{
+			"> exec hello world\n[stdout]\nhello, world\n> stdout '^bye'\n",
+			"testdata/fail/stdout.txt:3: no match for `^bye` found in stdout",
+		}},
+		{"cmp.txt", []string{
+			"testdata/fail/cmp.txt:2: stdout and want.txt differ (-want.txt +stdout):\n- hello, you\n+ hello, me",
+		}},
+		{"unknown.txt", []string{
+			"testdata/fail/unknown.txt:1: unknown command \"frobnicate\"",
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.file, func(t *testing.T) {
+			r := asserttest.ExpectFailure(t, func(t testing.TB) {
+				script.RunFile(t, "testdata/fail/"+c.file)
+			})
+			msgs := r.Messages("Fatal")
+			if len(msgs) != 1 {
+				t.Fatalf("expected one failure, got %q", msgs)
+			}
+			for _, want := range c.want {
+				if !strings.Contains(msgs[0], want) {
+					t.Errorf("message should contain %q, got:\n%s", want, msgs[0])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,445 @@
+package script
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"syscall"
+
+	"github.com/xandalm/go-testing/internal/diff"
+)
+
+// state is the state of a running script.
+type state struct {
+	c   *config
+	dir string
+	env []string
+
+	stdin          *string
+	stdout, stderr string
+	background     []*background
+	// log is the log of the commands run and their outputs, shown when
+	// the script fails.
+	log strings.Builder
+}
+
+// background is a program run in the background.
+type background struct {
+	name           string
+	neg            bool
+	cmd            *exec.Cmd
+	stdout, stderr bytes.Buffer
+	done           chan error
+}
+
+func newState(c *config, work string) *state {
+	s := &state{c: c, dir: work, env: os.Environ()}
+	s.setenv("WORK", work)
+	s.setenv("PWD", work)
+	return s
+}
+
+func (s *state) getenv(key string) string {
+	for i := len(s.env) - 1; i >= 0; i-- {
+		if k, v, _ := strings.Cut(s.env[i], "="); k == key {
+			return v
+		}
+	}
+	return ""
+}
+
+func (s *state) setenv(key, value string) {
+	s.env = append(s.env, key+"="+value)
+}
+
+type command func(s *state, neg bool, args []string) error
+
+var commands = map[string]command{
+	"exec":   (*state).cmdExec,
+	"stdin":  (*state).cmdStdin,
+	"stdout": (*state).cmdStdout,
+	"stderr": (*state).cmdStderr,
+	"cmp":    (*state).cmdCmp,
+	"env":    (*state).cmdEnv,
+	"cd":     (*state).cmdCd,
+	"wait":   (*state).cmdWait,
+	"kill":   (*state).cmdKill,
+}
+
+// exec runs the command of a script line.
+func (s *state) exec(line string) error {
+	line = strings.TrimSpace(line)
+	if line == "" || line[0] == '#' {
+		return nil
+	}
+	fmt.Fprintf(&s.log, "> %s\n", line)
+
+	rest, neg := strings.CutPrefix(line, "!")
+	args, err := s.split(strings.TrimSpace(rest))
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return errors.New("missing command")
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+	return cmd(s, neg, args[1:])
+}
+
+// split splits line into arguments, at spaces out of single quotes, and
+// expands the environment variables out of single quotes.
+func (s *state) split(line string) ([]string, error) {
+	var args []string
+	var arg strings.Builder
+	inArg, quoted := false, false
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quoted && c == '\'':
+			if i+1 < len(line) && line[i+1] == '\'' {
+				arg.WriteByte('\'')
+				i++
+			} else {
+				quoted = false
+			}
+		case quoted:
+			arg.WriteByte(c)
+		case c == '\'':
+			quoted, inArg = true, true
+		case c == ' ' || c == '\t':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		case c == '$':
+			name, n := varName(line[i+1:])
+			if n == 0 {
+				arg.WriteByte(c)
+			} else {
+				arg.WriteString(s.getenv(name))
+				i += n
+			}
+			inArg = true
+		default:
+			arg.WriteByte(c)
+			inArg = true
+		}
+	}
+	if quoted {
+		return nil, errors.New("unterminated quote")
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return args, nil
+}
+
+// varName returns the variable name at the start of s, as NAME or
+// {NAME}, and the length it takes.
+func varName(s string) (string, int) {
+	if strings.HasPrefix(s, "{") {
+		if end := strings.IndexByte(s, '}'); end > 1 {
+			return s[1:end], end + 1
+		}
+		return "", 0
+	}
+	n := 0
+	for n < len(s) && (s[n] == '_' || 'a' <= s[n] && s[n] <= 'z' || 'A' <= s[n] && s[n] <= 'Z' || '0' <= s[n] && s[n] <= '9') {
+		n++
+	}
+	return s[:n], n
+}
+
+func (s *state) path(name string) string {
+	if filepath.IsAbs(name) {
+		return name
+	}
+	return filepath.Join(s.dir, name)
+}
+
+// read returns the content of the file name, or of the output of the last
+// exec for stdout and stderr.
+func (s *state) read(name string) (string, error) {
+	switch name {
+	case "stdout":
+		return s.stdout, nil
+	case "stderr":
+		return s.stderr, nil
+	}
+	b, err := os.ReadFile(s.path(name))
+	return string(b), err
+}
+
+func (s *state) logOutput() {
+	for _, out := range []struct{ name, text string }{{"stdout", s.stdout}, {"stderr", s.stderr}} {
+		if out.text == "" {
+			continue
+		}
+		fmt.Fprintf(&s.log, "[%s]\n%s", out.name, out.text)
+		if !strings.HasSuffix(out.text, "\n") {
+			s.log.WriteByte('\n')
+		}
+	}
+}
+
+func noNeg(name string, neg bool) error {
+	if neg {
+		return fmt.Errorf("unsupported: ! %s", name)
+	}
+	return nil
+}
+
+func usage(format string) error {
+	return fmt.Errorf("usage: %s", format)
+}
+
+// program returns the command running the program name, registered by
+// RunMain, built by Program or else found in PATH.
+func (s *state) program(name string, args []string) (*exec.Cmd, error) {
+	var cmd *exec.Cmd
+	env := s.env
+	if _, ok := programs[name]; ok {
+		exe, err := os.Executable()
+		if err != nil {
+			return nil, err
+		}
+		cmd = exec.Command(exe, args...)
+		env = append(env[:len(env):len(env)], programEnv+"="+name)
+	} else if exe, ok := s.c.built[name]; ok {
+		cmd = exec.Command(exe, args...)
+	} else {
+		cmd = exec.Command(name, args...)
+	}
+	cmd.Dir = s.dir
+	cmd.Env = env
+	return cmd, nil
+}
+
+// exited checks how a program exited, as expected or not to fail.
+func exited(err error, neg bool) error {
+	var exit *exec.ExitError
+	switch {
+	case err != nil && !errors.As(err, &exit):
+		return err
+	case neg && err == nil:
+		return errors.New("unexpected command success")
+	case !neg && err != nil:
+		return fmt.Errorf("unexpected command failure: %v", err)
+	}
+	return nil
+}
+
+func (s *state) cmdExec(neg bool, args []string) error {
+	inBackground := len(args) > 0 && args[len(args)-1] == "&"
+	if inBackground {
+		args = args[:len(args)-1]
+	}
+	if len(args) == 0 {
+		return usage("exec program [args...] [&]")
+	}
+	cmd, err := s.program(args[0], args[1:])
+	if err != nil {
+		return err
+	}
+	if s.stdin != nil {
+		cmd.Stdin = strings.NewReader(*s.stdin)
+		s.stdin = nil
+	}
+
+	if inBackground {
+		b := &background{name: args[0], neg: neg, cmd: cmd, done: make(chan error, 1)}
+		cmd.Stdout, cmd.Stderr = &b.stdout, &b.stderr
+		if err := cmd.Start(); err != nil {
+			return err
+		}
+		go func() {
+			b.done <- cmd.Wait()
+		}()
+		s.background = append(s.background, b)
+		return nil
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	err = cmd.Run()
+	s.stdout, s.stderr = stdout.String(), stderr.String()
+	s.logOutput()
+	return exited(err, neg)
+}
+
+func (s *state) cmdStdin(neg bool, args []string) error {
+	if err := noNeg("stdin", neg); err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return usage("stdin file")
+	}
+	text, err := s.read(args[0])
+	if err != nil {
+		return err
+	}
+	s.stdin = &text
+	return nil
+}
+
+func (s *state) cmdStdout(neg bool, args []string) error {
+	return match("stdout", s.stdout, neg, args)
+}
+
+func (s *state) cmdStderr(neg bool, args []string) error {
+	return match("stderr", s.stderr, neg, args)
+}
+
+// match matches the regexp of args against the lines of text.
+func match(name, text string, neg bool, args []string) error {
+	if len(args) != 1 {
+		return usage(name + " regexp")
+	}
+	re, err := regexp.Compile("(?m)" + args[0])
+	if err != nil {
+		return err
+	}
+	switch loc := re.FindStringIndex(text); {
+	case neg && loc != nil:
+		return fmt.Errorf("unexpected match for %#q found in %s: %s", args[0], name, text[loc[0]:loc[1]])
+	case !neg && loc == nil:
+		return fmt.Errorf("no match for %#q found in %s", args[0], name)
+	}
+	return nil
+}
+
+func (s *state) cmdCmp(neg bool, args []string) error {
+	if len(args) != 2 {
+		return usage("cmp file1 file2")
+	}
+	got, err := s.read(args[0])
+	if err != nil {
+		return err
+	}
+	want, err := s.read(args[1])
+	if err != nil {
+		return err
+	}
+	switch {
+	case neg && got == want:
+		return fmt.Errorf("%s and %s don't differ", args[0], args[1])
+	case !neg && got != want:
+		return fmt.Errorf("%s and %s differ (-%s +%s):%s", args[0], args[1], args[1], args[0], diff.Content([]byte(got), []byte(want), "\n"))
+	}
+	return nil
+}
+
+func (s *state) cmdEnv(neg bool, args []string) error {
+	if err := noNeg("env", neg); err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return usage("env key=value...")
+	}
+	for _, arg := range args {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			fmt.Fprintf(&s.log, "%s=%s\n", key, s.getenv(key))
+			continue
+		}
+		s.setenv(key, value)
+	}
+	return nil
+}
+
+func (s *state) cmdCd(neg bool, args []string) error {
+	if err := noNeg("cd", neg); err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return usage("cd dir")
+	}
+	dir := s.path(args[0])
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s isn't a directory", args[0])
+	}
+	s.dir = dir
+	s.setenv("PWD", dir)
+	return nil
+}
+
+// waitBackground waits for the background programs, setting stdout and
+// stderr to their outputs. Unless killed, they must exit as expected.
+func (s *state) waitBackground(killed bool) error {
+	var stdout, stderr strings.Builder
+	var errs []error
+	for _, b := range s.background {
+		err := <-b.done
+		stdout.Write(b.stdout.Bytes())
+		stderr.Write(b.stderr.Bytes())
+		if err := exited(err, b.neg); err != nil && !killed {
+			errs = append(errs, fmt.Errorf("%s: %v", b.name, err))
+		}
+	}
+	s.background = nil
+	s.stdout, s.stderr = stdout.String(), stderr.String()
+	s.logOutput()
+	return errors.Join(errs...)
+}
+
+func (s *state) cmdWait(neg bool, args []string) error {
+	if err := noNeg("wait", neg); err != nil {
+		return err
+	}
+	if len(args) != 0 {
+		return usage("wait")
+	}
+	return s.waitBackground(false)
+}
+
+var signals = map[string]os.Signal{
+	"INT":  os.Interrupt,
+	"KILL": os.Kill,
+	"TERM": syscall.SIGTERM,
+}
+
+func (s *state) cmdKill(neg bool, args []string) error {
+	if err := noNeg("kill", neg); err != nil {
+		return err
+	}
+	sig := os.Kill
+	switch {
+	case len(args) > 1:
+		return usage("kill [-signal]")
+	case len(args) == 1:
+		var ok bool
+		if sig, ok = signals[strings.TrimPrefix(args[0], "-")]; !ok {
+			return fmt.Errorf("unknown signal %s", args[0])
+		}
+	}
+	for _, b := range s.background {
+		if err := b.cmd.Process.Signal(sig); err != nil && !errors.Is(err, os.ErrProcessDone) {
+			return err
+		}
+	}
+	return s.waitBackground(true)
+}
+
+// stop kills the background programs left.
+func (s *state) stop() {
+	for _, b := range s.background {
+		b.cmd.Process.Kill()
+	}
+	for _, b := range s.background {
+		<-b.done
+	}
+	s.background = nil
+}
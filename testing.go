@@ -4,12 +4,18 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"os"
 	"os/exec"
 	"runtime"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/xandalm/go-testing/clock"
 )
 
+//go:generate go run ./cmd -o mock_checker_test.go -pkg testing_test . AvailabilityChecker
+
 type AvailabilityChecker interface {
 	// Should return error if unable to ping (didn't pong)
 	Ping() error
@@ -28,11 +34,16 @@ func (c *HTTPServerChecker) Ping() error {
 }
 
 type ServerLauncher struct {
-	ctx  context.Context
-	wd   string
-	name string
-	c    AvailabilityChecker
-	cmd  *exec.Cmd
+	ctx   context.Context
+	wd    string
+	name  string
+	c     AvailabilityChecker
+	cmd   *exec.Cmd
+	clock clock.Clock
+	env   []string
+
+	cleanOnce sync.Once
+	cleanErr  error
 }
 
 func NewServerLauncher(ctx context.Context, wd, filename string, checker AvailabilityChecker) *ServerLauncher {
@@ -48,7 +59,35 @@ func NewServerLauncher(ctx context.Context, wd, filename string, checker Availab
 	if !strings.HasSuffix(filename, ".go") {
 		panic("testing: must be a go file (.go)")
 	}
-	return &ServerLauncher{ctx, wd, strings.TrimSuffix(filename, ".go"), checker, nil}
+	return &ServerLauncher{
+		ctx:   ctx,
+		wd:    wd,
+		name:  strings.TrimSuffix(filename, ".go"),
+		c:     checker,
+		clock: clock.New(),
+	}
+}
+
+// WithClock makes the launcher time out waiting for the server with c,
+// rather than with the time package.
+func (s *ServerLauncher) WithClock(c clock.Clock) *ServerLauncher {
+	if c == nil {
+		panic("testing: nil clock")
+	}
+	s.clock = c
+	return s
+}
+
+// WithEnv adds the environment variables of env, as "key=value", to those
+// the server inherits.
+func (s *ServerLauncher) WithEnv(env ...string) *ServerLauncher {
+	for _, kv := range env {
+		if !strings.Contains(kv, "=") {
+			panic("testing: invalid environment variable " + kv)
+		}
+	}
+	s.env = append(s.env, env...)
+	return s
 }
 
 func ping(c AvailabilityChecker) chan bool {
@@ -59,29 +98,69 @@ func ping(c AvailabilityChecker) chan bool {
 	return ch
 }
 
+// Build builds the Go program of src, a file or a package directory
+// relative to wd, into the executable out, or into wd as named by go build
+// when out is empty. ServerLauncher builds its server so.
+func Build(ctx context.Context, wd, src, out string) error {
+	args := []string{"build"}
+	if out != "" {
+		args = append(args, "-o", out)
+	}
+	cmd := exec.CommandContext(ctx, "go", append(args, src)...)
+	cmd.Dir = wd
+	if b, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%v\n%s", err, b)
+	}
+	return nil
+}
+
 func (s *ServerLauncher) build() error {
-	cmd := exec.CommandContext(s.ctx, "go", "build", s.name+".go")
-	cmd.Dir = s.wd
-	return cmd.Run()
+	return Build(s.ctx, s.wd, s.name+".go", "")
 }
 
-func (s *ServerLauncher) wait() chan struct{} {
+// pingInterval is the time waited between pings of a server not answering
+// yet.
+const pingInterval = 50 * time.Millisecond
+
+// wait pings the server until it answers, the context is done or stop is
+// closed, and then closes the returned channel.
+func (s *ServerLauncher) wait(stop <-chan struct{}) chan struct{} {
 	ch := make(chan struct{})
-	for {
-		select {
-		case res := <-ping(s.c):
-			if res {
-				close(ch)
-				return ch
+	go func() {
+		defer close(ch)
+		for {
+			select {
+			case res := <-ping(s.c):
+				if res {
+					return
+				}
+			case <-s.ctx.Done():
+				return
+			case <-stop:
+				return
+			}
+			select {
+			case <-s.clock.After(pingInterval):
+			case <-s.ctx.Done():
+				return
+			case <-stop:
+				return
 			}
-		case <-s.ctx.Done():
-			close(ch)
-			return ch
 		}
-	}
+	}()
+	return ch
 }
 
+// clean removes the server executable once, as both the failing server
+// and EndAndClean clean.
 func (s *ServerLauncher) clean() error {
+	s.cleanOnce.Do(func() {
+		s.cleanErr = s.remove()
+	})
+	return s.cleanErr
+}
+
+func (s *ServerLauncher) remove() error {
 	var cmd *exec.Cmd
 	if runtime.GOOS == "windows" {
 		cmd = exec.CommandContext(s.ctx, "cmd", "/C", fmt.Sprintf("del %s.exe", s.name))
@@ -109,17 +188,26 @@ func (s *ServerLauncher) StartAndWait(waitFor time.Duration) error {
 
 	s.cmd = exec.CommandContext(s.ctx, "./"+s.name)
 	s.cmd.Dir = s.wd
+	if len(s.env) > 0 {
+		s.cmd.Env = append(os.Environ(), s.env...)
+	}
 
+	if err := s.cmd.Start(); err != nil {
+		s.clean()
+		return fmt.Errorf("testing: cannot build and start server, %v", err)
+	}
 	go func() {
-		if err := s.cmd.Run(); err != nil {
+		if err := s.cmd.Wait(); err != nil {
 			s.clean()
 		}
 	}()
 
+	stop := make(chan struct{})
+	defer close(stop)
 	select {
-	case <-s.wait():
+	case <-s.wait(stop):
 		return nil
-	case <-time.After(waitFor):
+	case <-s.clock.After(waitFor):
 		s.EndAndClean()
 		return fmt.Errorf("testing: cannot start server")
 	}
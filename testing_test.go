@@ -2,18 +2,24 @@ package testing_test
 
 import (
 	"context"
+	"errors"
+	"io"
 	"net/http"
 	"testing"
 	"time"
 
 	tpkg "github.com/xandalm/go-testing"
+	"github.com/xandalm/go-testing/assert"
+	"github.com/xandalm/go-testing/assert/fsassert"
+	"github.com/xandalm/go-testing/assert/mockhttp"
+	"github.com/xandalm/go-testing/clock"
 )
 
 func TestServerLauncher(t *testing.T) {
 	ctx := context.Background()
 	launcher := tpkg.NewServerLauncher(
 		ctx,
-		"cmd/",
+		"internal/server/",
 		"main.go",
 		&tpkg.HTTPServerChecker{
 			"http://localhost:5000",
@@ -31,3 +37,48 @@ func TestServerLauncher(t *testing.T) {
 		t.Errorf("cannot graceful shutdown the server, %v", err)
 	}
 }
+
+func TestServerLauncherEnv(t *testing.T) {
+	upstream := mockhttp.NewServer(t)
+	// the pings of the checker reach the upstream too
+	upstream.Expect("GET", "/greeting").Respond(http.StatusOK, "Hi from upstream").Times(-1)
+	launcher := tpkg.NewServerLauncher(
+		context.Background(),
+		"internal/server/",
+		"main.go",
+		&tpkg.HTTPServerChecker{"http://localhost:5000", &http.Client{}},
+	).WithEnv(upstream.Env("UPSTREAM_URL"))
+
+	if err := launcher.StartAndWait(5 * time.Second); err != nil {
+		t.Fatalf("cannot start the server, %v", err)
+	}
+	defer launcher.EndAndClean()
+
+	res, err := http.Get("http://localhost:5000/")
+	assert.Nil(t, err)
+	defer res.Body.Close()
+	body, _ := io.ReadAll(res.Body)
+	assert.Equal(t, string(body), "Hi from upstream")
+	upstream.Verify(t)
+}
+
+func TestServerLauncherTimeout(t *testing.T) {
+	checker := NewMockAvailabilityChecker()
+	checker.ExpectPing().Return(errors.New("connection refused")).Times(-1)
+	fake := clock.NewFake(time.Now())
+	launcher := tpkg.NewServerLauncher(context.Background(), "internal/server/", "main.go", checker).WithClock(fake)
+
+	errc := make(chan error)
+	go func() {
+		errc <- launcher.StartAndWait(5 * time.Second)
+	}()
+	// the timeout and the wait between pings
+	fake.BlockUntil(2)
+	fake.Advance(5 * time.Second)
+
+	err := <-errc
+	assert.NotNil(t, err)
+	assert.Equal(t, err.Error(), "testing: cannot start server")
+	checker.Verify(t)
+	fsassert.NoFileExists(t, nil, "internal/server/main")
+}